@@ -24,14 +24,124 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/messages/image": {
+        "/chats/{jid}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the inbound and outbound messages of a chat recorded by this server, newest first. Pass next_cursor from the response as cursor to get older messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chats"
+                ],
+                "summary": "Get the messages of a chat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chat JID or phone number",
+                        "name": "jid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of messages to return, at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages of this type, such as text, image or reaction",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages from this phone number or JID",
+                        "name": "sender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only outbound (true) or inbound (false) messages",
+                        "name": "from_me",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages sent at or after this RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages sent before this RFC 3339 time",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the raw protobuf of each message as base64",
+                        "name": "include_raw",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/storage.MessagePage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Message store is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/audio": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Sends an image message to a WhatsApp number",
+                "description": "Sends an audio file or a push-to-talk voice note to a WhatsApp number. Voice notes are converted to OGG/Opus if needed.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -41,7 +151,7 @@ const docTemplate = `{
                 "tags": [
                     "messages"
                 ],
-                "summary": "Send an image message",
+                "summary": "Send an audio message",
                 "parameters": [
                     {
                         "type": "string",
@@ -52,21 +162,87 @@ const docTemplate = `{
                     },
                     {
                         "type": "file",
-                        "description": "Image file",
-                        "name": "image",
+                        "description": "Audio file (OGG/Opus recommended for voice notes)",
+                        "name": "audio",
                         "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Send as a push-to-talk voice note",
+                        "name": "ptt",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message to quote as a JSON object",
+                        "name": "reply_to",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Image sent successfully",
+                        "description": "Audio sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/messages/audio/json": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends an audio file or push-to-talk voice note fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send an audio message by URL or base64",
+                "parameters": [
+                    {
+                        "description": "Audio details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AudioJSONRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audio sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
                     },
                     "400": {
                         "description": "Invalid request",
@@ -89,14 +265,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/messages/text": {
+        "/messages/contact": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Sends a text message to a WhatsApp number",
+                "description": "Sends one or more contacts as vCards to a WhatsApp number. A single contact is sent as a contact message, several as a contacts array.",
                 "consumes": [
                     "application/json"
                 ],
@@ -106,27 +282,110 @@ const docTemplate = `{
                 "tags": [
                     "messages"
                 ],
-                "summary": "Send a text message",
+                "summary": "Send contact cards",
                 "parameters": [
                     {
-                        "description": "Message details",
+                        "description": "Contact details",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/handlers.ContactRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Message sent successfully",
+                        "description": "Contact sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/messages/document": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a document (PDF, spreadsheet, invoice, etc.) with an optional caption to a WhatsApp number",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a document message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipient's phone number",
+                        "name": "to",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Document file",
+                        "name": "document",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File name shown to the recipient (defaults to the uploaded file name)",
+                        "name": "filename",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document title (defaults to the file name)",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document caption",
+                        "name": "caption",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message to quote as a JSON object",
+                        "name": "reply_to",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
                     },
                     "400": {
                         "description": "Invalid request",
@@ -149,9 +408,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/ws": {
-            "get": {
-                "description": "Establishes a WebSocket connection to receive WhatsApp QR codes and connection status updates",
+        "/messages/document/json": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a document fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required. The file name defaults to the last segment of the URL and is required for base64 data.",
                 "consumes": [
                     "application/json"
                 ],
@@ -159,18 +423,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "websocket"
+                    "messages"
+                ],
+                "summary": "Send a document message by URL or base64",
+                "parameters": [
+                    {
+                        "description": "Document details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DocumentJSONRequest"
+                        }
+                    }
                 ],
-                "summary": "WebSocket connection for WhatsApp QR code and status updates",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols",
+                    "200": {
+                        "description": "Document sent successfully",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Error upgrading connection",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -180,6 +464,2442 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/messages/edit": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replaces the text of a message sent through this server. WhatsApp only allows edits within 20 minutes of sending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Edit a sent text message",
+                "parameters": [
+                    {
+                        "description": "Edit details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EditRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message edited successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Message can no longer be edited",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/image": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a JPEG, PNG, GIF or WebP image with an optional caption to a WhatsApp number",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send an image message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipient's phone number",
+                        "name": "to",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image caption",
+                        "name": "caption",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Send as a view once message",
+                        "name": "view_once",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message to quote as a JSON object",
+                        "name": "reply_to",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/image/json": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends an image fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send an image message by URL or base64",
+                "parameters": [
+                    {
+                        "description": "Image details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImageJSONRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/location": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a location pin, or a live location update when live is true, to a WhatsApp number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a location message",
+                "parameters": [
+                    {
+                        "description": "Location details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Location sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/poll": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a poll to a WhatsApp number or group. The returned ID is used to query the results.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Create a poll",
+                "parameters": [
+                    {
+                        "description": "Poll details",
+                        "name": "poll",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Poll sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/reaction": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Reacts to a message with an emoji, or removes the reaction when the emoji is empty",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "React to a message",
+                "parameters": [
+                    {
+                        "description": "Reaction details",
+                        "name": "reaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reaction sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revokes a sent message. The chat can be omitted for messages sent through this server.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Delete a message for everyone",
+                "parameters": [
+                    {
+                        "description": "Revoke details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RevokeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message revoked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/sticker": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a PNG, JPEG or WebP image as a sticker to a WhatsApp number. Images are converted to a 512x512 WebP if needed.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a sticker message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipient's phone number",
+                        "name": "to",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Sticker image",
+                        "name": "sticker",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message to quote as a JSON object",
+                        "name": "reply_to",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sticker sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/sticker/json": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a PNG, JPEG or WebP image fetched from a URL or decoded from base64 data as a sticker to a WhatsApp number. Exactly one of url or data is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a sticker message by URL or base64",
+                "parameters": [
+                    {
+                        "description": "Sticker details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StickerJSONRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sticker sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/text": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a text message to a WhatsApp number or group, optionally as a reply to an earlier message. Every mentioned number must appear as @number in the message. Set link_preview to attach a preview card for the first URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a text message",
+                "parameters": [
+                    {
+                        "description": "Message details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TextRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Message sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/video": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a video message with an optional caption to a WhatsApp number",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a video message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recipient's phone number",
+                        "name": "to",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Video file (MP4 recommended)",
+                        "name": "video",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Video caption",
+                        "name": "caption",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message to quote as a JSON object",
+                        "name": "reply_to",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Video sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/video/json": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sends a video fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "messages"
+                ],
+                "summary": "Send a video message by URL or base64",
+                "parameters": [
+                    {
+                        "description": "Video details",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VideoJSONRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Video sent successfully",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/polls/{id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the vote tally of a poll sent or seen since the server started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "polls"
+                ],
+                "summary": "Get poll results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Poll message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/whatsapp.PollResults"
+                        }
+                    },
+                    "404": {
+                        "description": "Poll not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/session/logout": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Unlinks the session from its WhatsApp account and deletes the device from the store. A new QR code is generated so the session can be linked again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Log out",
+                "responses": {
+                    "200": {
+                        "description": "Logged out successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Session is not logged in",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/session/pair-phone": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns an 8-character code that links this server to the WhatsApp account of a phone number. On the phone, open Linked devices, choose Link with phone number instead and enter the code. The code is also sent to WebSocket clients as a pair_code frame.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Pair by phone number",
+                "parameters": [
+                    {
+                        "description": "Phone number",
+                        "name": "pairing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PairPhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PairPhoneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Session is already logged in",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/session/qr": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the latest QR code of a session that isn't logged in, as text and as a PNG data URI. WhatsApp replaces the code every 20 seconds or so. A new login is started when the last one has timed out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Get the login QR code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Image width and height in pixels, between 64 and 2048. Defaults to QR_SIZE.",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.QRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid size",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No QR code available yet",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Session is already logged in",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/session/qr.png": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the latest QR code of a session that isn't logged in as a PNG image, for clients that can't render QR codes themselves",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Get the login QR code as PNG",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Image width and height in pixels, between 64 and 2048. Defaults to QR_SIZE.",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR code",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid size",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No QR code available yet",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Session is already logged in",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/session/qr.svg": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the latest QR code of a session that isn't logged in as an SVG image, for clients that can't render QR codes themselves",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Get the login QR code as SVG",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Image width and height in pixels, between 64 and 2048. Defaults to QR_SIZE.",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR code",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid size",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No QR code available yet",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Session is already logged in",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/session/status": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns whether the session is connected and logged in, the linked account, its push name and platform, and when it last connected",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Get the session status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/whatsapp.SessionStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns every WhatsApp session served by this server, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/whatsapp.SessionInfo"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a session for another WhatsApp account and starts its login. Connect to /ws?session={id} to receive the QR code to scan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Create a session",
+                "parameters": [
+                    {
+                        "description": "Session details",
+                        "name": "session",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/whatsapp.SessionInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Session already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns a WhatsApp session and whether it is connected and logged in",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/whatsapp.SessionInfo"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Logs the session out of WhatsApp, removes its device, closes its WebSocket connections and stops serving it. Its stored messages are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Delete a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the registered webhooks. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhook.Webhook"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Registers a URL that receives WhatsApp events as JSON POST requests. Failed deliveries are retried with exponential backoff and end up in the dead-letter list. Webhooks registered here are kept in memory only and are gone after a restart.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook details",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the events that still failed after every delivery attempt, oldest first, including events dropped because the webhook queue was full. Only the most recent 1000 are kept, in memory.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List undelivered webhook events",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhook.DeadLetter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Stops delivering events to a webhook and drops the events still waiting for it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "Establishes a WebSocket connection to receive WhatsApp QR codes, connection status updates and event frames for messages, receipts, presence, typing and group updates. Send subscribe and unsubscribe frames to filter events, an unsubscribe frame without events, chats or groups clears the filters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "websocket"
+                ],
+                "summary": "WebSocket connection for WhatsApp QR code, status updates and events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key, for clients that can't set the X-API-Key header",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "default",
+                        "description": "Session ID",
+                        "name": "session",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Error upgrading connection",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid API key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Origin not allowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AudioJSONRequest": {
+            "type": "object",
+            "required": [
+                "to"
+            ],
+            "properties": {
+                "data": {
+                    "description": "Data is the base64 encoded media, optionally as a data URI",
+                    "type": "string",
+                    "example": "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+                },
+                "ptt": {
+                    "type": "boolean"
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "url": {
+                    "description": "URL is fetched by the server, only http and https are allowed",
+                    "type": "string",
+                    "example": "https://example.com/photo.jpg"
+                }
+            }
+        },
+        "handlers.ContactRequest": {
+            "type": "object",
+            "required": [
+                "contacts",
+                "to"
+            ],
+            "properties": {
+                "contacts": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/whatsapp.Contact"
+                    }
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                }
+            }
+        },
+        "handlers.DocumentJSONRequest": {
+            "type": "object",
+            "required": [
+                "to"
+            ],
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "example": "Your invoice"
+                },
+                "data": {
+                    "description": "Data is the base64 encoded media, optionally as a data URI",
+                    "type": "string",
+                    "example": "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "invoice.pdf"
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Invoice #42"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "url": {
+                    "description": "URL is fetched by the server, only http and https are allowed",
+                    "type": "string",
+                    "example": "https://example.com/photo.jpg"
+                }
+            }
+        },
+        "handlers.EditRequest": {
+            "type": "object",
+            "required": [
+                "message",
+                "message_id"
+            ],
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Hello, World! (edited)"
+                },
+                "message_id": {
+                    "type": "string",
+                    "example": "3EB0C431C26A1916E5F6"
+                }
+            }
+        },
+        "handlers.ImageJSONRequest": {
+            "type": "object",
+            "required": [
+                "to"
+            ],
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "example": "Check this out"
+                },
+                "data": {
+                    "description": "Data is the base64 encoded media, optionally as a data URI",
+                    "type": "string",
+                    "example": "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "url": {
+                    "description": "URL is fetched by the server, only http and https are allowed",
+                    "type": "string",
+                    "example": "https://example.com/photo.jpg"
+                },
+                "view_once": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.LocationRequest": {
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude",
+                "to"
+            ],
+            "properties": {
+                "accuracy_in_meters": {
+                    "description": "The fields below only apply to live locations",
+                    "type": "integer",
+                    "example": 10
+                },
+                "address": {
+                    "type": "string",
+                    "example": "Jl. Sudirman No. 1, Jakarta"
+                },
+                "caption": {
+                    "type": "string",
+                    "example": "On my way"
+                },
+                "heading": {
+                    "type": "integer",
+                    "maximum": 359,
+                    "example": 90
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": -6.2088
+                },
+                "live": {
+                    "description": "Live sends a live location update instead of a static pin",
+                    "type": "boolean"
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 106.8456
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Pickup point"
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "sequence_number": {
+                    "type": "integer"
+                },
+                "speed_in_mps": {
+                    "type": "number",
+                    "example": 8.5
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://maps.example.com/pickup"
+                }
+            }
+        },
+        "handlers.PairPhoneRequest": {
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "phone": {
+                    "description": "Phone is the number of the WhatsApp account to link, in international format",
+                    "type": "string",
+                    "example": "6281234567890"
+                }
+            }
+        },
+        "handlers.PairPhoneResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "ABCD-EFGH"
+                }
+            }
+        },
+        "handlers.PollRequest": {
+            "type": "object",
+            "required": [
+                "options",
+                "question",
+                "to"
+            ],
+            "properties": {
+                "options": {
+                    "type": "array",
+                    "maxItems": 12,
+                    "minItems": 2,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Monday",
+                        "Tuesday",
+                        "Wednesday"
+                    ]
+                },
+                "question": {
+                    "type": "string",
+                    "example": "Which day works best?"
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "selectable_count": {
+                    "description": "SelectableCount is how many options a voter may pick, 0 allows any number",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                }
+            }
+        },
+        "handlers.QRResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the text encoded in the QR code",
+                    "type": "string",
+                    "example": "2@a1b2c3..."
+                },
+                "image": {
+                    "description": "Image is the QR code as a PNG data URI",
+                    "type": "string",
+                    "example": "data:image/png;base64,iVBORw0KGgo..."
+                }
+            }
+        },
+        "handlers.ReactionRequest": {
+            "type": "object",
+            "required": [
+                "chat",
+                "message_id"
+            ],
+            "properties": {
+                "chat": {
+                    "description": "Chat is the phone number or JID of the chat containing the message",
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "emoji": {
+                    "description": "Emoji is the reaction, an empty string removes a previous reaction",
+                    "type": "string",
+                    "example": "✅"
+                },
+                "from_me": {
+                    "description": "FromMe marks the message as sent by this account",
+                    "type": "boolean"
+                },
+                "message_id": {
+                    "description": "MessageID is the ID of the message",
+                    "type": "string",
+                    "example": "3EB0C431C26A1916E5F6"
+                },
+                "sender": {
+                    "description": "Sender is the phone number or JID of the message's author.\nRequired for other people's messages in groups, defaults to the chat in private chats.",
+                    "type": "string",
+                    "example": "1234567890"
+                }
+            }
+        },
+        "handlers.RevokeRequest": {
+            "type": "object",
+            "required": [
+                "message_id"
+            ],
+            "properties": {
+                "chat": {
+                    "description": "Chat is required for messages that weren't sent through this server",
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "message_id": {
+                    "type": "string",
+                    "example": "3EB0C431C26A1916E5F6"
+                },
+                "sender": {
+                    "description": "Sender lets group admins revoke other members' messages",
+                    "type": "string",
+                    "example": "1234567891"
+                }
+            }
+        },
+        "handlers.SendResponse": {
+            "type": "object",
+            "properties": {
+                "debug_timings": {
+                    "$ref": "#/definitions/whatsapp.DebugTimings"
+                },
+                "id": {
+                    "description": "ID is the message ID, used to reply to, react to, edit or revoke the message",
+                    "type": "string",
+                    "example": "3EB0C431C26A1916E5F6"
+                },
+                "recipient": {
+                    "type": "string",
+                    "example": "1234567890@s.whatsapp.net"
+                },
+                "server_id": {
+                    "description": "ServerID is only set for newsletter messages",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Message sent successfully"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2025-05-01T13:06:09Z"
+                }
+            }
+        },
+        "handlers.SessionRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID names the session in URLs, a random ID is generated when empty",
+                    "type": "string",
+                    "example": "sales"
+                }
+            }
+        },
+        "handlers.StickerJSONRequest": {
+            "type": "object",
+            "required": [
+                "to"
+            ],
+            "properties": {
+                "data": {
+                    "description": "Data is the base64 encoded media, optionally as a data URI",
+                    "type": "string",
+                    "example": "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "url": {
+                    "description": "URL is fetched by the server, only http and https are allowed",
+                    "type": "string",
+                    "example": "https://example.com/photo.jpg"
+                }
+            }
+        },
+        "handlers.TextRequest": {
+            "type": "object",
+            "required": [
+                "message",
+                "to"
+            ],
+            "properties": {
+                "link_preview": {
+                    "description": "LinkPreview attaches a preview card for the first URL in the message",
+                    "type": "boolean",
+                    "example": true
+                },
+                "mentions": {
+                    "description": "Mentions lists the phone numbers or JIDs tagged as @number in the message",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "1234567891"
+                    ]
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Hello @1234567891!"
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                }
+            }
+        },
+        "handlers.VideoJSONRequest": {
+            "type": "object",
+            "required": [
+                "to"
+            ],
+            "properties": {
+                "caption": {
+                    "type": "string",
+                    "example": "Product demo"
+                },
+                "data": {
+                    "description": "Data is the base64 encoded media, optionally as a data URI",
+                    "type": "string",
+                    "example": "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+                },
+                "reply_to": {
+                    "description": "ReplyTo quotes an earlier message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/whatsapp.ReplyTo"
+                        }
+                    ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "url": {
+                    "description": "URL is fetched by the server, only http and https are allowed",
+                    "type": "string",
+                    "example": "https://example.com/photo.jpg"
+                }
+            }
+        },
+        "handlers.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "Events limits the event types delivered: message, receipt, presence, typing, group or connection.\nAll events are delivered when empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "message"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret signs every delivery with HMAC-SHA256 in the X-Whrabbit-Signature header",
+                    "type": "string",
+                    "example": "change-me"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/whatsapp/events"
+                }
+            }
+        },
+        "storage.Message": {
+            "type": "object",
+            "properties": {
+                "chat": {
+                    "type": "string",
+                    "example": "1234567890@s.whatsapp.net"
+                },
+                "from_me": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3EB0C431C26A1916E5F6"
+                },
+                "raw": {
+                    "description": "Raw is the protobuf encoded message, only loaded when requested",
+                    "type": "string",
+                    "format": "base64"
+                },
+                "sender": {
+                    "type": "string",
+                    "example": "1234567890@s.whatsapp.net"
+                },
+                "session": {
+                    "description": "Session is the ID of the session that sent or received the message",
+                    "type": "string",
+                    "example": "default"
+                },
+                "stored_at": {
+                    "description": "StoredAt is when the message was recorded by this server",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "Text is the message text or media caption, if any",
+                    "type": "string",
+                    "example": "Hello, World!"
+                },
+                "timestamp": {
+                    "description": "Timestamp is when the message was sent according to WhatsApp",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is the kind of content, such as text, image, reaction or poll",
+                    "type": "string",
+                    "example": "text"
+                }
+            }
+        },
+        "storage.MessagePage": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/storage.Message"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the next (older) page, empty on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "event": {
+                    "$ref": "#/definitions/whatsapp.Event"
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Events limits the event types delivered, all events are delivered when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "message"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "4f1d2c3b5a697887"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/whatsapp/events"
+                }
+            }
+        },
+        "whatsapp.Contact": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "jane@example.com"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                },
+                "org": {
+                    "type": "string",
+                    "example": "Example Corp"
+                },
+                "phones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "+6281234567890"
+                    ]
+                }
+            }
+        },
+        "whatsapp.DebugTimings": {
+            "type": "object",
+            "properties": {
+                "get_devices": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "get_participants": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "group_encrypt": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "marshal": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "peer_encrypt": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "queue": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "resp": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "retry": {
+                    "type": "string",
+                    "example": "1.2ms"
+                },
+                "send": {
+                    "type": "string",
+                    "example": "1.2ms"
+                }
+            }
+        },
+        "whatsapp.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "id": {
+                    "description": "ID is unique per event, receivers can use it to drop duplicates",
+                    "type": "string",
+                    "example": "9f2c4e1a7b3d5f60"
+                },
+                "session": {
+                    "description": "Session is the ID of the session that received the event",
+                    "type": "string",
+                    "example": "default"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "message"
+                }
+            }
+        },
+        "whatsapp.PollOptionResult": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Monday"
+                },
+                "voters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "votes": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "whatsapp.PollResults": {
+            "type": "object",
+            "properties": {
+                "chat": {
+                    "type": "string",
+                    "example": "1234567890@s.whatsapp.net"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3EB0C431C26A1916E5F6"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/whatsapp.PollOptionResult"
+                    }
+                },
+                "question": {
+                    "type": "string",
+                    "example": "Which day works best?"
+                },
+                "selectable_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total_voters": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "whatsapp.ReplyTo": {
+            "type": "object",
+            "required": [
+                "message_id"
+            ],
+            "properties": {
+                "message_id": {
+                    "type": "string",
+                    "example": "3EB0C431C26A1916E5F6"
+                },
+                "sender": {
+                    "description": "Sender is the JID or phone number of the quoted message's author.\nRequired in groups, defaults to the recipient in private chats.",
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "text": {
+                    "description": "Text is shown in the quote preview",
+                    "type": "string",
+                    "example": "Where is my order?"
+                }
+            }
+        },
+        "whatsapp.SessionInfo": {
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "sales"
+                },
+                "jid": {
+                    "description": "JID is the linked WhatsApp account, empty until the session is paired",
+                    "type": "string",
+                    "example": "6281234567890:12@s.whatsapp.net"
+                },
+                "logged_in": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "whatsapp.SessionStatus": {
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "type": "boolean"
+                },
+                "jid": {
+                    "description": "JID is the linked WhatsApp account, empty until the session is paired",
+                    "type": "string",
+                    "example": "6281234567890:12@s.whatsapp.net"
+                },
+                "last_connected": {
+                    "description": "LastConnected is when the session last connected to WhatsApp",
+                    "type": "string"
+                },
+                "logged_in": {
+                    "type": "boolean"
+                },
+                "platform": {
+                    "description": "Platform is the platform of the phone the session is linked to",
+                    "type": "string",
+                    "example": "android"
+                },
+                "push_name": {
+                    "type": "string",
+                    "example": "Jane"
+                },
+                "session": {
+                    "type": "string",
+                    "example": "default"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/storage"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// GetChatMessages returns the stored messages of a chat
+// @Summary Get the messages of a chat
+// @Description Returns the inbound and outbound messages of a chat recorded by this server, newest first. Pass next_cursor from the response as cursor to get older messages.
+// @Tags chats
+// @Produce json
+// @Param jid path string true "Chat JID or phone number"
+// @Param limit query int false "Number of messages to return, at most 200" default(50)
+// @Param cursor query string false "Cursor from a previous page"
+// @Param type query string false "Only messages of this type, such as text, image or reaction"
+// @Param sender query string false "Only messages from this phone number or JID"
+// @Param from_me query boolean false "Only outbound (true) or inbound (false) messages"
+// @Param since query string false "Only messages sent at or after this RFC 3339 time"
+// @Param until query string false "Only messages sent before this RFC 3339 time"
+// @Param include_raw query boolean false "Include the raw protobuf of each message as base64"
+// @Success 200 {object} storage.MessagePage
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Message store is not configured"
+// @Security Bearer
+// @Router /chats/{jid}/messages [get]
+func (h *MessageHandler) GetChatMessages(c *gin.Context) {
+	query, err := messageQueryFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.clientFor(c).GetMessages(c.Param("jid"), query)
+	switch {
+	case errors.Is(err, storage.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, whatsapp.ErrNoMessageStore):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// messageQueryFromRequest reads the message filters from the query string
+func messageQueryFromRequest(c *gin.Context) (storage.MessageQuery, error) {
+	query := storage.MessageQuery{
+		Cursor: c.Query("cursor"),
+		Type:   c.Query("type"),
+		Sender: c.Query("sender"),
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return query, fmt.Errorf("limit must be a positive number")
+		}
+		query.Limit = limit
+	}
+	if value := c.Query("from_me"); value != "" {
+		fromMe, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("from_me must be a boolean")
+		}
+		query.FromMe = &fromMe
+	}
+	if value := c.Query("include_raw"); value != "" {
+		includeRaw, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("include_raw must be a boolean")
+		}
+		query.IncludeRaw = includeRaw
+	}
+	if value := c.Query("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("since must be an RFC 3339 time")
+		}
+		query.Since = since
+	}
+	if value := c.Query("until"); value != "" {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("until must be an RFC 3339 time")
+		}
+		query.Until = until
+	}
+
+	return query, nil
+}
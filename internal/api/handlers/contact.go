@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// ContactRequest is the body for sending contact cards
+type ContactRequest struct {
+	To       string             `json:"to" binding:"required" example:"1234567890"`
+	Contacts []whatsapp.Contact `json:"contacts" binding:"required,min=1,dive"`
+	SendOptionsRequest
+}
+
+// SendContact sends one or more contact cards
+// @Summary Send contact cards
+// @Description Sends one or more contacts as vCards to a WhatsApp number. A single contact is sent as a contact message, several as a contacts array.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body ContactRequest true "Contact details"
+// @Success 200 {object} SendResponse "Contact sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/contact [post]
+func (h *MessageHandler) SendContact(c *gin.Context) {
+	var req ContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the contacts using the WhatsApp client
+	result, err := h.clientFor(c).SendContacts(req.To, req.Contacts, req.sendOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Contact sent successfully", result))
+}
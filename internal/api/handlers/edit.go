@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// EditRequest is the body for editing a sent text message
+type EditRequest struct {
+	MessageID string `json:"message_id" binding:"required" example:"3EB0C431C26A1916E5F6"`
+	Message   string `json:"message" binding:"required" example:"Hello, World! (edited)"`
+}
+
+// RevokeRequest is the body for deleting a message for everyone
+type RevokeRequest struct {
+	MessageID string `json:"message_id" binding:"required" example:"3EB0C431C26A1916E5F6"`
+	// Chat is required for messages that weren't sent through this server
+	Chat string `json:"chat" example:"1234567890"`
+	// Sender lets group admins revoke other members' messages
+	Sender string `json:"sender" example:"1234567891"`
+}
+
+// EditMessage edits a sent text message
+// @Summary Edit a sent text message
+// @Description Replaces the text of a message sent through this server. WhatsApp only allows edits within 20 minutes of sending.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body EditRequest true "Edit details"
+// @Success 200 {object} SendResponse "Message edited successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Message not found"
+// @Failure 422 {object} map[string]string "Message can no longer be edited"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/edit [post]
+func (h *MessageHandler) EditMessage(c *gin.Context) {
+	var req EditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Edit the message using the WhatsApp client
+	result, err := h.clientFor(c).EditText(req.MessageID, req.Message)
+	switch {
+	case errors.Is(err, whatsapp.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, whatsapp.ErrEditWindowExpired), errors.Is(err, whatsapp.ErrNotEditable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Message edited successfully", result))
+}
+
+// RevokeMessage deletes a message for everyone
+// @Summary Delete a message for everyone
+// @Description Revokes a sent message. The chat can be omitted for messages sent through this server.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body RevokeRequest true "Revoke details"
+// @Success 200 {object} SendResponse "Message revoked successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Message not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/revoke [post]
+func (h *MessageHandler) RevokeMessage(c *gin.Context) {
+	var req RevokeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Revoke the message using the WhatsApp client
+	result, err := h.clientFor(c).RevokeMessage(req.Chat, req.MessageID, req.Sender)
+	if errors.Is(err, whatsapp.ErrMessageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Message revoked successfully", result))
+}
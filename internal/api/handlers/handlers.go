@@ -1,27 +1,60 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/w33ladalah/whrabbit/internal/api/middleware"
 	ws "github.com/w33ladalah/whrabbit/internal/api/websocket"
+	"github.com/w33ladalah/whrabbit/internal/config"
 	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
+// wsAuthTimeout is how long a WebSocket client has to send its auth frame
+const wsAuthTimeout = 10 * time.Second
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins
-	},
+	CheckOrigin:     checkOrigin,
+}
+
+// checkOrigin allows requests without an Origin header, same-origin requests and the
+// origins listed in WS_ALLOWED_ORIGINS, where "*" allows every origin
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range config.GetWSAllowedOrigins() {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, r.Host)
 }
 
 // WebSocketHandler handles WebSocket connections
 type WebSocketHandler struct {
-	manager *ws.Manager
+	manager  *ws.Manager
+	sessions *whatsapp.SessionManager
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
@@ -38,46 +71,213 @@ func NewWebSocketHandlerWithManager(manager *ws.Manager) *WebSocketHandler {
 	}
 }
 
+// NewSessionWebSocketHandler creates a WebSocket handler that streams the QR codes and
+// events of the session named by the session query parameter
+func NewSessionWebSocketHandler(sessions *whatsapp.SessionManager) *WebSocketHandler {
+	return &WebSocketHandler{
+		sessions: sessions,
+	}
+}
+
 // GetManager returns the WebSocket manager
 func (h *WebSocketHandler) GetManager() *ws.Manager {
 	return h.manager
 }
 
 // HandleWebSocket handles WebSocket connections
-// @Summary WebSocket connection for WhatsApp QR code and status updates
-// @Description Establishes a WebSocket connection to receive WhatsApp QR codes and connection status updates
+// @Summary WebSocket connection for WhatsApp QR code, status updates and events
+// @Description Establishes a WebSocket connection to receive WhatsApp QR codes, connection status updates and event frames for messages, receipts, presence, typing and group updates. Send subscribe and unsubscribe frames to filter events, an unsubscribe frame without events, chats or groups clears the filters.
 // @Tags websocket
 // @Accept json
 // @Produce json
 // @Success 101 {string} string "Switching Protocols"
-// @Failure 500 {object} map[string]string "Error upgrading connection"
+// @Param token query string false "API key, for clients that can't set the X-API-Key header"
+// @Param session query string false "Session ID" default(default)
+// @Failure 400 {object} map[string]string "Error upgrading connection"
+// @Failure 401 {object} map[string]string "Invalid API key"
+// @Failure 403 {object} map[string]string "Origin not allowed"
+// @Failure 404 {object} map[string]string "Session not found"
 // @Router /ws [get]
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
+	// Browsers can't set headers on WebSocket requests, so they authenticate with the
+	// token query parameter or an auth frame instead
+	apiKey := c.GetHeader("X-API-Key")
+	if apiKey == "" {
+		apiKey = c.Query("token")
+	}
+	if apiKey != "" && !middleware.ValidAPIKey(apiKey) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing API key"})
+		return
+	}
+
+	// Only reveal whether a session exists to authenticated clients
+	sessionID := c.DefaultQuery("session", whatsapp.DefaultSessionID)
+	var manager *ws.Manager
+	if apiKey != "" {
+		var err error
+		if manager, err = h.sessionManager(sessionID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upgrade connection"})
+		// The upgrader has already written an error response
 		return
 	}
 
-	h.manager.AddClient(conn)
+	if apiKey == "" {
+		if err := authenticateFrame(conn); err != nil {
+			log.Printf("WebSocket authentication failed: %v", err)
+			closeWithError(conn, "Invalid or missing API key", "unauthorized")
+			return
+		}
+		if manager, err = h.sessionManager(sessionID); err != nil {
+			closeWithError(conn, err.Error(), err.Error())
+			return
+		}
+	}
+
+	manager.AddClient(conn)
 
 	// Handle client disconnection
 	defer func() {
-		h.manager.RemoveClient(conn)
+		manager.RemoveClient(conn)
 	}()
 
-	// Keep connection alive
+	// Read subscription frames until the client disconnects
 	for {
-		_, _, err := conn.ReadMessage()
+		_, data, err := conn.ReadMessage()
 		if err != nil {
 			break
 		}
+		handleFrame(manager, conn, data)
+	}
+}
+
+// sessionManager returns the WebSocket manager of a session
+func (h *WebSocketHandler) sessionManager(sessionID string) (*ws.Manager, error) {
+	if h.sessions == nil {
+		return h.manager, nil
 	}
+	client, ok := h.sessions.Get(sessionID)
+	if !ok {
+		return nil, whatsapp.ErrSessionNotFound
+	}
+	return client.GetWebSocketManager(), nil
+}
+
+// closeWithError sends an error frame and closes the connection with a policy violation
+func closeWithError(conn *websocket.Conn, message, reason string) {
+	conn.WriteJSON(map[string]string{"type": "error", "error": message})
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason), time.Now().Add(time.Second))
+	conn.Close()
+}
+
+// AuthFrame is the first frame sent by WebSocket clients that didn't pass an API key
+// in the X-API-Key header or token query parameter
+type AuthFrame struct {
+	// Action is auth
+	Action string `json:"action"`
+	APIKey string `json:"api_key"`
+}
+
+// authenticateFrame waits for an auth frame with a valid API key
+func authenticateFrame(conn *websocket.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(wsAuthTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var frame AuthFrame
+	if err := conn.ReadJSON(&frame); err != nil {
+		return fmt.Errorf("error reading auth frame: %v", err)
+	}
+	if frame.Action != "auth" || !middleware.ValidAPIKey(frame.APIKey) {
+		return fmt.Errorf("invalid auth frame")
+	}
+	return conn.WriteJSON(map[string]string{"type": "authenticated"})
+}
+
+// SubscriptionFrame is sent by WebSocket clients to choose which events they receive
+type SubscriptionFrame struct {
+	// Action is subscribe or unsubscribe
+	Action string `json:"action"`
+	// Events are event types such as message, receipt, presence, typing or group
+	Events []string `json:"events"`
+	// Chats are chat JIDs or phone numbers
+	Chats []string `json:"chats"`
+	// Groups are group JIDs, the @g.us suffix is optional
+	Groups []string `json:"groups"`
+}
+
+// subscriptionResponse confirms a client's subscription after every change
+type subscriptionResponse struct {
+	Type string `json:"type"`
+	ws.Subscription
+}
+
+// handleFrame applies a subscription frame from a client and replies with the
+// resulting subscription or an error frame
+func handleFrame(manager *ws.Manager, conn *websocket.Conn, data []byte) {
+	frame, err := parseSubscriptionFrame(data)
+	if err != nil {
+		if err := manager.SendJSON(conn, map[string]string{"type": "error", "error": err.Error()}); err != nil {
+			log.Printf("Error sending error frame to client: %v", err)
+		}
+		return
+	}
+
+	var sub ws.Subscription
+	if frame.Action == "subscribe" {
+		sub = manager.Subscribe(conn, frame.Events, frame.Chats)
+	} else {
+		sub = manager.Unsubscribe(conn, frame.Events, frame.Chats)
+	}
+	if err := manager.SendJSON(conn, subscriptionResponse{Type: "subscription", Subscription: sub}); err != nil {
+		log.Printf("Error sending subscription to client: %v", err)
+	}
+}
+
+// parseSubscriptionFrame validates a subscription frame and normalizes its chats and
+// groups to JIDs in Chats
+func parseSubscriptionFrame(data []byte) (*SubscriptionFrame, error) {
+	var frame SubscriptionFrame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return nil, fmt.Errorf("invalid frame: %v", err)
+	}
+	if frame.Action != "subscribe" && frame.Action != "unsubscribe" {
+		return nil, fmt.Errorf("action must be subscribe or unsubscribe")
+	}
+
+	for _, eventType := range frame.Events {
+		if !slices.Contains(whatsapp.EventTypes, eventType) {
+			return nil, fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+
+	chats := frame.Chats
+	for _, group := range frame.Groups {
+		if !strings.ContainsRune(group, '@') {
+			group += "@" + types.GroupServer
+		}
+		chats = append(chats, group)
+	}
+	frame.Chats, frame.Groups = nil, nil
+	for _, chat := range chats {
+		jid, err := whatsapp.ParseJID(chat)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat %q: %v", chat, err)
+		}
+		frame.Chats = append(frame.Chats, jid.ToNonAD().String())
+	}
+
+	return &frame, nil
 }
 
 // MessageHandler handles WhatsApp messages
 type MessageHandler struct {
-	client *whatsapp.Client
+	client   *whatsapp.Client
+	sessions *whatsapp.SessionManager
 }
 
 // NewMessageHandler creates a new message handler
@@ -87,48 +287,113 @@ func NewMessageHandler(client *whatsapp.Client) *MessageHandler {
 	}
 }
 
+// NewSessionMessageHandler creates a message handler that uses the client of the
+// session chosen by the ResolveSession middleware
+func NewSessionMessageHandler(sessions *whatsapp.SessionManager) *MessageHandler {
+	return &MessageHandler{
+		sessions: sessions,
+	}
+}
+
+// SendOptionsRequest holds the options accepted by every JSON send request
+type SendOptionsRequest struct {
+	// ReplyTo quotes an earlier message
+	ReplyTo *whatsapp.ReplyTo `json:"reply_to"`
+}
+
+// sendOptions converts the request options to WhatsApp client options
+func (r SendOptionsRequest) sendOptions() whatsapp.SendOptions {
+	return whatsapp.SendOptions{
+		ReplyTo: r.ReplyTo,
+	}
+}
+
+// sendOptionsFromForm reads the send options from multipart form fields.
+// reply_to is given as a JSON object.
+func sendOptionsFromForm(c *gin.Context) (whatsapp.SendOptions, error) {
+	var req SendOptionsRequest
+	if value := c.PostForm("reply_to"); value != "" {
+		req.ReplyTo = &whatsapp.ReplyTo{}
+		if err := json.Unmarshal([]byte(value), req.ReplyTo); err != nil {
+			return whatsapp.SendOptions{}, fmt.Errorf("reply_to must be a JSON object: %v", err)
+		}
+		if req.ReplyTo.MessageID == "" {
+			return whatsapp.SendOptions{}, fmt.Errorf("reply_to message_id is required")
+		}
+	}
+	return req.sendOptions(), nil
+}
+
+// SendResponse is returned by the send endpoints. The message ID can be used to
+// reply to, react to, edit or revoke the message later.
+type SendResponse struct {
+	Status string `json:"status" example:"Message sent successfully"`
+	whatsapp.SendResult
+}
+
+// sendResponse builds the response for a sent message
+func sendResponse(status string, result *whatsapp.SendResult) SendResponse {
+	return SendResponse{Status: status, SendResult: *result}
+}
+
+// TextRequest is the body for sending a text message
+type TextRequest struct {
+	To      string `json:"to" binding:"required" example:"1234567890"`
+	Message string `json:"message" binding:"required" example:"Hello @1234567891!"`
+	// Mentions lists the phone numbers or JIDs tagged as @number in the message
+	Mentions []string `json:"mentions" example:"1234567891"`
+	// LinkPreview attaches a preview card for the first URL in the message
+	LinkPreview bool `json:"link_preview" example:"true"`
+	SendOptionsRequest
+}
+
 // SendText sends a text message
 // @Summary Send a text message
-// @Description Sends a text message to a WhatsApp number
+// @Description Sends a text message to a WhatsApp number or group, optionally as a reply to an earlier message. Every mentioned number must appear as @number in the message. Set link_preview to attach a preview card for the first URL.
 // @Tags messages
 // @Accept json
 // @Produce json
-// @Param message body object true "Message details" SchemaExample({"to": "1234567890", "message": "Hello, World!"})
-// @Success 200 {object} map[string]string "Message sent successfully"
+// @Param message body TextRequest true "Message details"
+// @Success 200 {object} SendResponse "Message sent successfully"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Security Bearer
 // @Router /messages/text [post]
 func (h *MessageHandler) SendText(c *gin.Context) {
-	var req struct {
-		To      string `json:"to" binding:"required"`
-		Message string `json:"message" binding:"required"`
-	}
+	var req TextRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := whatsapp.ValidateMentions(req.Message, req.Mentions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Send the message using the WhatsApp client
-	err := h.client.SendText(req.To, req.Message)
+	result, err := h.clientFor(c).SendText(req.To, req.Message, req.Mentions, req.LinkPreview, req.sendOptions())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "Message sent successfully"})
+	c.JSON(http.StatusOK, sendResponse("Message sent successfully", result))
 }
 
 // SendImage sends an image message
 // @Summary Send an image message
-// @Description Sends an image message to a WhatsApp number
+// @Description Sends a JPEG, PNG, GIF or WebP image with an optional caption to a WhatsApp number
 // @Tags messages
 // @Accept multipart/form-data
 // @Produce json
 // @Param to formData string true "Recipient's phone number"
 // @Param image formData file true "Image file"
-// @Success 200 {object} map[string]string "Image sent successfully"
+// @Param caption formData string false "Image caption"
+// @Param view_once formData boolean false "Send as a view once message"
+// @Param reply_to formData string false "Message to quote as a JSON object"
+// @Success 200 {object} SendResponse "Image sent successfully"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Security Bearer
@@ -146,6 +411,21 @@ func (h *MessageHandler) SendImage(c *gin.Context) {
 		return
 	}
 
+	viewOnce := false
+	if value := c.PostForm("view_once"); value != "" {
+		viewOnce, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "view_once must be a boolean"})
+			return
+		}
+	}
+
+	opts, err := sendOptionsFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Open the uploaded file
 	src, err := file.Open()
 	if err != nil {
@@ -155,13 +435,236 @@ func (h *MessageHandler) SendImage(c *gin.Context) {
 	defer src.Close()
 
 	// Send the image using the WhatsApp client
-	err = h.client.SendImage(to, src)
+	result, err := h.clientFor(c).SendImage(to, src, c.PostForm("caption"), viewOnce, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Image sent successfully", result))
+}
+
+// SendVideo sends a video message
+// @Summary Send a video message
+// @Description Sends a video message with an optional caption to a WhatsApp number
+// @Tags messages
+// @Accept multipart/form-data
+// @Produce json
+// @Param to formData string true "Recipient's phone number"
+// @Param video formData file true "Video file (MP4 recommended)"
+// @Param caption formData string false "Video caption"
+// @Param reply_to formData string false "Message to quote as a JSON object"
+// @Success 200 {object} SendResponse "Video sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/video [post]
+func (h *MessageHandler) SendVideo(c *gin.Context) {
+	to := c.PostForm("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient number is required"})
+		return
+	}
+
+	file, err := c.FormFile("video")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Video file is required"})
+		return
+	}
+
+	opts, err := sendOptionsFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Open the uploaded file
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open video file"})
+		return
+	}
+	defer src.Close()
+
+	// Send the video using the WhatsApp client
+	result, err := h.clientFor(c).SendVideo(to, src, c.PostForm("caption"), opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Video sent successfully", result))
+}
+
+// SendDocument sends a document message
+// @Summary Send a document message
+// @Description Sends a document (PDF, spreadsheet, invoice, etc.) with an optional caption to a WhatsApp number
+// @Tags messages
+// @Accept multipart/form-data
+// @Produce json
+// @Param to formData string true "Recipient's phone number"
+// @Param document formData file true "Document file"
+// @Param filename formData string false "File name shown to the recipient (defaults to the uploaded file name)"
+// @Param title formData string false "Document title (defaults to the file name)"
+// @Param caption formData string false "Document caption"
+// @Param reply_to formData string false "Message to quote as a JSON object"
+// @Success 200 {object} SendResponse "Document sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/document [post]
+func (h *MessageHandler) SendDocument(c *gin.Context) {
+	to := c.PostForm("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient number is required"})
+		return
+	}
+
+	file, err := c.FormFile("document")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Document file is required"})
+		return
+	}
+
+	fileName := c.PostForm("filename")
+	if fileName == "" {
+		fileName = file.Filename
+	}
+
+	opts, err := sendOptionsFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Open the uploaded file
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open document file"})
+		return
+	}
+	defer src.Close()
+
+	// Send the document using the WhatsApp client
+	result, err := h.clientFor(c).SendDocument(to, src, fileName, c.PostForm("title"), c.PostForm("caption"), opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Document sent successfully", result))
+}
+
+// SendAudio sends an audio message
+// @Summary Send an audio message
+// @Description Sends an audio file or a push-to-talk voice note to a WhatsApp number. Voice notes are converted to OGG/Opus if needed.
+// @Tags messages
+// @Accept multipart/form-data
+// @Produce json
+// @Param to formData string true "Recipient's phone number"
+// @Param audio formData file true "Audio file (OGG/Opus recommended for voice notes)"
+// @Param ptt formData boolean false "Send as a push-to-talk voice note"
+// @Param reply_to formData string false "Message to quote as a JSON object"
+// @Success 200 {object} SendResponse "Audio sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/audio [post]
+func (h *MessageHandler) SendAudio(c *gin.Context) {
+	to := c.PostForm("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient number is required"})
+		return
+	}
+
+	file, err := c.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Audio file is required"})
+		return
+	}
+
+	ptt := false
+	if value := c.PostForm("ptt"); value != "" {
+		ptt, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ptt must be a boolean"})
+			return
+		}
+	}
+
+	opts, err := sendOptionsFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Open the uploaded file
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open audio file"})
+		return
+	}
+	defer src.Close()
+
+	// Send the audio using the WhatsApp client
+	result, err := h.clientFor(c).SendAudio(to, src, ptt, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Audio sent successfully", result))
+}
+
+// SendSticker sends a sticker message
+// @Summary Send a sticker message
+// @Description Sends a PNG, JPEG or WebP image as a sticker to a WhatsApp number. Images are converted to a 512x512 WebP if needed.
+// @Tags messages
+// @Accept multipart/form-data
+// @Produce json
+// @Param to formData string true "Recipient's phone number"
+// @Param sticker formData file true "Sticker image"
+// @Param reply_to formData string false "Message to quote as a JSON object"
+// @Success 200 {object} SendResponse "Sticker sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/sticker [post]
+func (h *MessageHandler) SendSticker(c *gin.Context) {
+	to := c.PostForm("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient number is required"})
+		return
+	}
+
+	file, err := c.FormFile("sticker")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sticker file is required"})
+		return
+	}
+
+	opts, err := sendOptionsFromForm(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Open the uploaded file
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open sticker file"})
+		return
+	}
+	defer src.Close()
+
+	// Send the sticker using the WhatsApp client
+	result, err := h.clientFor(c).SendSticker(to, src, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"status": "Image sent successfully"})
+	c.JSON(http.StatusOK, sendResponse("Sticker sent successfully", result))
 }
 
 // NewEventHandler creates a new event handler function
@@ -174,12 +677,12 @@ func NewEventHandler(client *whatsapp.Client) func(interface{}) {
 		case *events.Connected:
 			// Handle successful connection
 			if client.GetWebSocketManager() != nil {
-				client.GetWebSocketManager().BroadcastConnectionStatus("WhatsApp connected successfully!")
+				client.GetWebSocketManager().BroadcastConnectionStatus(ws.StateConnected, "WhatsApp connected successfully!")
 			}
 		case *events.Disconnected:
 			// Handle disconnection
 			if client.GetWebSocketManager() != nil {
-				client.GetWebSocketManager().BroadcastConnectionStatus("WhatsApp disconnected")
+				client.GetWebSocketManager().BroadcastConnectionStatus(ws.StateDisconnected, "WhatsApp disconnected")
 			}
 		}
 	}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// LocationRequest is the body for sending a location or live location
+type LocationRequest struct {
+	To        string   `json:"to" binding:"required" example:"1234567890"`
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90" example:"-6.2088"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180" example:"106.8456"`
+	Name      string   `json:"name" example:"Pickup point"`
+	Address   string   `json:"address" example:"Jl. Sudirman No. 1, Jakarta"`
+	URL       string   `json:"url" example:"https://maps.example.com/pickup"`
+	// Live sends a live location update instead of a static pin
+	Live bool `json:"live"`
+	// The fields below only apply to live locations
+	AccuracyInMeters uint32  `json:"accuracy_in_meters" example:"10"`
+	SpeedInMps       float32 `json:"speed_in_mps" example:"8.5"`
+	Heading          uint32  `json:"heading" binding:"max=359" example:"90"`
+	Caption          string  `json:"caption" example:"On my way"`
+	SequenceNumber   int64   `json:"sequence_number"`
+	SendOptionsRequest
+}
+
+// SendLocation sends a location message
+// @Summary Send a location message
+// @Description Sends a location pin, or a live location update when live is true, to a WhatsApp number
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body LocationRequest true "Location details"
+// @Success 200 {object} SendResponse "Location sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/location [post]
+func (h *MessageHandler) SendLocation(c *gin.Context) {
+	var req LocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the location using the WhatsApp client
+	var result *whatsapp.SendResult
+	var err error
+	if req.Live {
+		result, err = h.clientFor(c).SendLiveLocation(req.To, whatsapp.LiveLocation{
+			Latitude:         *req.Latitude,
+			Longitude:        *req.Longitude,
+			AccuracyInMeters: req.AccuracyInMeters,
+			SpeedInMps:       req.SpeedInMps,
+			Heading:          req.Heading,
+			Caption:          req.Caption,
+			SequenceNumber:   req.SequenceNumber,
+		}, req.sendOptions())
+	} else {
+		result, err = h.clientFor(c).SendLocation(req.To, whatsapp.Location{
+			Latitude:  *req.Latitude,
+			Longitude: *req.Longitude,
+			Name:      req.Name,
+			Address:   req.Address,
+			URL:       req.URL,
+		}, req.sendOptions())
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Location sent successfully", result))
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/config"
+	"github.com/w33ladalah/whrabbit/internal/qr"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// PairPhoneRequest is the body for pairing by phone number
+type PairPhoneRequest struct {
+	// Phone is the number of the WhatsApp account to link, in international format
+	Phone string `json:"phone" binding:"required" example:"6281234567890"`
+}
+
+// PairPhoneResponse holds the code to enter on the phone
+type PairPhoneResponse struct {
+	Code string `json:"code" example:"ABCD-EFGH"`
+}
+
+// PairPhone links the session with a code instead of a QR code
+// @Summary Pair by phone number
+// @Description Returns an 8-character code that links this server to the WhatsApp account of a phone number. On the phone, open Linked devices, choose Link with phone number instead and enter the code. The code is also sent to WebSocket clients as a pair_code frame.
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param pairing body PairPhoneRequest true "Phone number"
+// @Success 200 {object} PairPhoneResponse
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 409 {object} map[string]string "Session is already logged in"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /session/pair-phone [post]
+func (h *MessageHandler) PairPhone(c *gin.Context) {
+	var req PairPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	code, err := h.clientFor(c).PairPhone(req.Phone)
+	switch {
+	case errors.Is(err, whatsapp.ErrAlreadyLoggedIn):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PairPhoneResponse{Code: code})
+}
+
+// QRResponse holds the QR code to scan
+type QRResponse struct {
+	// Code is the text encoded in the QR code
+	Code string `json:"code" example:"2@a1b2c3..."`
+	// Image is the QR code as a PNG data URI
+	Image string `json:"image" example:"data:image/png;base64,iVBORw0KGgo..."`
+}
+
+// GetSessionStatus returns the login state of the session
+// @Summary Get the session status
+// @Description Returns whether the session is connected and logged in, the linked account, its push name and platform, and when it last connected
+// @Tags session
+// @Produce json
+// @Success 200 {object} whatsapp.SessionStatus
+// @Security Bearer
+// @Router /session/status [get]
+func (h *MessageHandler) GetSessionStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.clientFor(c).Status())
+}
+
+// GetQR returns the QR code to scan
+// @Summary Get the login QR code
+// @Description Returns the latest QR code of a session that isn't logged in, as text and as a PNG data URI. WhatsApp replaces the code every 20 seconds or so. A new login is started when the last one has timed out.
+// @Tags session
+// @Produce json
+// @Param size query int false "Image width and height in pixels, between 64 and 2048. Defaults to QR_SIZE."
+// @Success 200 {object} QRResponse
+// @Failure 400 {object} map[string]string "Invalid size"
+// @Failure 404 {object} map[string]string "No QR code available yet"
+// @Failure 409 {object} map[string]string "Session is already logged in"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /session/qr [get]
+func (h *MessageHandler) GetQR(c *gin.Context) {
+	code, size, ok := h.latestQR(c)
+	if !ok {
+		return
+	}
+
+	image, err := qr.PNGDataURI(code, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, QRResponse{
+		Code:  code,
+		Image: image,
+	})
+}
+
+// GetQRPNG returns the QR code to scan as a PNG image
+// @Summary Get the login QR code as PNG
+// @Description Returns the latest QR code of a session that isn't logged in as a PNG image, for clients that can't render QR codes themselves
+// @Tags session
+// @Produce png
+// @Param size query int false "Image width and height in pixels, between 64 and 2048. Defaults to QR_SIZE."
+// @Success 200 {file} binary "QR code"
+// @Failure 400 {object} map[string]string "Invalid size"
+// @Failure 404 {object} map[string]string "No QR code available yet"
+// @Failure 409 {object} map[string]string "Session is already logged in"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /session/qr.png [get]
+func (h *MessageHandler) GetQRPNG(c *gin.Context) {
+	code, size, ok := h.latestQR(c)
+	if !ok {
+		return
+	}
+
+	png, err := qr.PNG(code, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// GetQRSVG returns the QR code to scan as an SVG image
+// @Summary Get the login QR code as SVG
+// @Description Returns the latest QR code of a session that isn't logged in as an SVG image, for clients that can't render QR codes themselves
+// @Tags session
+// @Produce image/svg+xml
+// @Param size query int false "Image width and height in pixels, between 64 and 2048. Defaults to QR_SIZE."
+// @Success 200 {file} binary "QR code"
+// @Failure 400 {object} map[string]string "Invalid size"
+// @Failure 404 {object} map[string]string "No QR code available yet"
+// @Failure 409 {object} map[string]string "Session is already logged in"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /session/qr.svg [get]
+func (h *MessageHandler) GetQRSVG(c *gin.Context) {
+	code, size, ok := h.latestQR(c)
+	if !ok {
+		return
+	}
+
+	svg, err := qr.SVG(code, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/svg+xml", svg)
+}
+
+// latestQR returns the QR code of the session and the requested image size. It writes
+// the error response and returns false when there is no code to render.
+func (h *MessageHandler) latestQR(c *gin.Context) (string, int, bool) {
+	size := config.GetQRSize()
+	if value := c.Query("size"); value != "" {
+		var err error
+		size, err = strconv.Atoi(value)
+		if err != nil || size < qr.MinSize || size > qr.MaxSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("size must be a number between %d and %d", qr.MinSize, qr.MaxSize)})
+			return "", 0, false
+		}
+	}
+
+	code, err := h.clientFor(c).LatestQR()
+	switch {
+	case errors.Is(err, whatsapp.ErrAlreadyLoggedIn):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return "", 0, false
+	case err != nil:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return "", 0, false
+	}
+	return code, size, true
+}
+
+// Logout logs the session out of WhatsApp
+// @Summary Log out
+// @Description Unlinks the session from its WhatsApp account and deletes the device from the store. A new QR code is generated so the session can be linked again.
+// @Tags session
+// @Produce json
+// @Success 200 {object} map[string]string "Logged out successfully"
+// @Failure 409 {object} map[string]string "Session is not logged in"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /session/logout [post]
+func (h *MessageHandler) Logout(c *gin.Context) {
+	err := h.clientFor(c).Logout()
+	switch {
+	case errors.Is(err, whatsapp.ErrNotLoggedIn):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "Logged out successfully"})
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// ImageJSONRequest is the body for sending an image by URL or base64 data
+type ImageJSONRequest struct {
+	To string `json:"to" binding:"required" example:"1234567890"`
+	whatsapp.MediaSource
+	Caption  string `json:"caption" example:"Check this out"`
+	ViewOnce bool   `json:"view_once"`
+	SendOptionsRequest
+}
+
+// VideoJSONRequest is the body for sending a video by URL or base64 data
+type VideoJSONRequest struct {
+	To string `json:"to" binding:"required" example:"1234567890"`
+	whatsapp.MediaSource
+	Caption string `json:"caption" example:"Product demo"`
+	SendOptionsRequest
+}
+
+// DocumentJSONRequest is the body for sending a document by URL or base64 data
+type DocumentJSONRequest struct {
+	To string `json:"to" binding:"required" example:"1234567890"`
+	whatsapp.MediaSource
+	FileName string `json:"filename" example:"invoice.pdf"`
+	Title    string `json:"title" example:"Invoice #42"`
+	Caption  string `json:"caption" example:"Your invoice"`
+	SendOptionsRequest
+}
+
+// AudioJSONRequest is the body for sending audio by URL or base64 data
+type AudioJSONRequest struct {
+	To string `json:"to" binding:"required" example:"1234567890"`
+	whatsapp.MediaSource
+	PTT bool `json:"ptt"`
+	SendOptionsRequest
+}
+
+// StickerJSONRequest is the body for sending a sticker by URL or base64 data
+type StickerJSONRequest struct {
+	To string `json:"to" binding:"required" example:"1234567890"`
+	whatsapp.MediaSource
+	SendOptionsRequest
+}
+
+// SendImageJSON sends an image message from a URL or base64 data
+// @Summary Send an image message by URL or base64
+// @Description Sends an image fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body ImageJSONRequest true "Image details"
+// @Success 200 {object} SendResponse "Image sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/image/json [post]
+func (h *MessageHandler) SendImageJSON(c *gin.Context) {
+	var req ImageJSONRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := whatsapp.LoadMedia(req.MediaSource, "image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the image using the WhatsApp client
+	result, err := h.clientFor(c).SendImage(req.To, media.Reader(), req.Caption, req.ViewOnce, req.sendOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Image sent successfully", result))
+}
+
+// SendVideoJSON sends a video message from a URL or base64 data
+// @Summary Send a video message by URL or base64
+// @Description Sends a video fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body VideoJSONRequest true "Video details"
+// @Success 200 {object} SendResponse "Video sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/video/json [post]
+func (h *MessageHandler) SendVideoJSON(c *gin.Context) {
+	var req VideoJSONRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := whatsapp.LoadMedia(req.MediaSource, "video")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the video using the WhatsApp client
+	result, err := h.clientFor(c).SendVideo(req.To, media.Reader(), req.Caption, req.sendOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Video sent successfully", result))
+}
+
+// SendDocumentJSON sends a document message from a URL or base64 data
+// @Summary Send a document message by URL or base64
+// @Description Sends a document fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required. The file name defaults to the last segment of the URL and is required for base64 data.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body DocumentJSONRequest true "Document details"
+// @Success 200 {object} SendResponse "Document sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/document/json [post]
+func (h *MessageHandler) SendDocumentJSON(c *gin.Context) {
+	var req DocumentJSONRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := whatsapp.LoadMedia(req.MediaSource, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = media.FileName
+	}
+	if fileName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File name is required"})
+		return
+	}
+
+	// Send the document using the WhatsApp client
+	result, err := h.clientFor(c).SendDocument(req.To, media.Reader(), fileName, req.Title, req.Caption, req.sendOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Document sent successfully", result))
+}
+
+// SendAudioJSON sends an audio message from a URL or base64 data
+// @Summary Send an audio message by URL or base64
+// @Description Sends an audio file or push-to-talk voice note fetched from a URL or decoded from base64 data to a WhatsApp number. Exactly one of url or data is required.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body AudioJSONRequest true "Audio details"
+// @Success 200 {object} SendResponse "Audio sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/audio/json [post]
+func (h *MessageHandler) SendAudioJSON(c *gin.Context) {
+	var req AudioJSONRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := whatsapp.LoadMedia(req.MediaSource, "audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the audio using the WhatsApp client
+	result, err := h.clientFor(c).SendAudio(req.To, media.Reader(), req.PTT, req.sendOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Audio sent successfully", result))
+}
+
+// SendStickerJSON sends a sticker message from a URL or base64 data
+// @Summary Send a sticker message by URL or base64
+// @Description Sends a PNG, JPEG or WebP image fetched from a URL or decoded from base64 data as a sticker to a WhatsApp number. Exactly one of url or data is required.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body StickerJSONRequest true "Sticker details"
+// @Success 200 {object} SendResponse "Sticker sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/sticker/json [post]
+func (h *MessageHandler) SendStickerJSON(c *gin.Context) {
+	var req StickerJSONRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	media, err := whatsapp.LoadMedia(req.MediaSource, "image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the sticker using the WhatsApp client
+	result, err := h.clientFor(c).SendSticker(req.To, media.Reader(), req.sendOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Sticker sent successfully", result))
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// PollRequest is the body for creating a poll
+type PollRequest struct {
+	To       string   `json:"to" binding:"required" example:"1234567890"`
+	Question string   `json:"question" binding:"required" example:"Which day works best?"`
+	Options  []string `json:"options" binding:"required,min=2,max=12" example:"Monday,Tuesday,Wednesday"`
+	// SelectableCount is how many options a voter may pick, 0 allows any number
+	SelectableCount int `json:"selectable_count" binding:"min=0" example:"1"`
+	SendOptionsRequest
+}
+
+// SendPoll creates a poll
+// @Summary Create a poll
+// @Description Sends a poll to a WhatsApp number or group. The returned ID is used to query the results.
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Param poll body PollRequest true "Poll details"
+// @Success 200 {object} SendResponse "Poll sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/poll [post]
+func (h *MessageHandler) SendPoll(c *gin.Context) {
+	var req PollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the poll using the WhatsApp client
+	result, err := h.clientFor(c).SendPoll(req.To, req.Question, req.Options, req.SelectableCount, req.sendOptions())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sendResponse("Poll sent successfully", result))
+}
+
+// GetPollResults returns the results of a poll
+// @Summary Get poll results
+// @Description Returns the vote tally of a poll sent or seen since the server started
+// @Tags polls
+// @Produce json
+// @Param id path string true "Poll message ID"
+// @Success 200 {object} whatsapp.PollResults
+// @Failure 404 {object} map[string]string "Poll not found"
+// @Security Bearer
+// @Router /polls/{id}/results [get]
+func (h *MessageHandler) GetPollResults(c *gin.Context) {
+	results, err := h.clientFor(c).GetPollResults(c.Param("id"))
+	if errors.Is(err, whatsapp.ErrPollNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
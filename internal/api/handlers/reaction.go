@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// ReactionRequest is the body for reacting to a message
+type ReactionRequest struct {
+	whatsapp.MessageRef
+	// Emoji is the reaction, an empty string removes a previous reaction
+	Emoji string `json:"emoji" example:"✅"`
+}
+
+// SendReaction reacts to a message
+// @Summary React to a message
+// @Description Reacts to a message with an emoji, or removes the reaction when the emoji is empty
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param reaction body ReactionRequest true "Reaction details"
+// @Success 200 {object} SendResponse "Reaction sent successfully"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /messages/reaction [post]
+func (h *MessageHandler) SendReaction(c *gin.Context) {
+	var req ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Send the reaction using the WhatsApp client
+	result, err := h.clientFor(c).SendReaction(req.MessageRef, req.Emoji)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := "Reaction sent successfully"
+	if req.Emoji == "" {
+		status = "Reaction removed successfully"
+	}
+	c.JSON(http.StatusOK, sendResponse(status, result))
+}
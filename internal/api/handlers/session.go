@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// sessionClientKey is the gin context key of the client chosen by ResolveSession
+const sessionClientKey = "whatsapp.client"
+
+// SessionHandler manages WhatsApp sessions
+type SessionHandler struct {
+	sessions *whatsapp.SessionManager
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessions *whatsapp.SessionManager) *SessionHandler {
+	return &SessionHandler{
+		sessions: sessions,
+	}
+}
+
+// SessionRequest is the body for creating a session
+type SessionRequest struct {
+	// ID names the session in URLs, a random ID is generated when empty
+	ID string `json:"id" example:"sales"`
+}
+
+// ListSessions returns every session
+// @Summary List sessions
+// @Description Returns every WhatsApp session served by this server, oldest first
+// @Tags sessions
+// @Produce json
+// @Success 200 {array} whatsapp.SessionInfo
+// @Security Bearer
+// @Router /sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.sessions.List())
+}
+
+// CreateSession creates a session
+// @Summary Create a session
+// @Description Creates a session for another WhatsApp account and starts its login. Connect to /ws?session={id} to receive the QR code to scan.
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param session body SessionRequest false "Session details"
+// @Success 201 {object} whatsapp.SessionInfo
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 409 {object} map[string]string "Session already exists"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /sessions [post]
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	// The body is optional
+	var req SessionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	client, err := h.sessions.Create(req.ID)
+	switch {
+	case errors.Is(err, whatsapp.ErrInvalidSessionID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, whatsapp.ErrSessionExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	info, err := h.sessions.Info(client.SessionID())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, info)
+}
+
+// GetSession returns a session
+// @Summary Get a session
+// @Description Returns a WhatsApp session and whether it is connected and logged in
+// @Tags sessions
+// @Produce json
+// @Param session path string true "Session ID"
+// @Success 200 {object} whatsapp.SessionInfo
+// @Failure 404 {object} map[string]string "Session not found"
+// @Security Bearer
+// @Router /sessions/{session} [get]
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	info, err := h.sessions.Info(c.Param("session"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// DeleteSession deletes a session
+// @Summary Delete a session
+// @Description Logs the session out of WhatsApp, removes its device, closes its WebSocket connections and stops serving it. Its stored messages are kept.
+// @Tags sessions
+// @Produce json
+// @Param session path string true "Session ID"
+// @Success 200 {object} map[string]string "Session deleted successfully"
+// @Failure 404 {object} map[string]string "Session not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /sessions/{session} [delete]
+func (h *SessionHandler) DeleteSession(c *gin.Context) {
+	err := h.sessions.Delete(c.Param("session"))
+	switch {
+	case errors.Is(err, whatsapp.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "Session deleted successfully"})
+}
+
+// ResolveSession is middleware that chooses the client of the session named by the
+// session path parameter, or of the default session on routes without one
+func (h *MessageHandler) ResolveSession(c *gin.Context) {
+	if h.sessions == nil {
+		c.Next()
+		return
+	}
+
+	id := c.Param("session")
+	if id == "" {
+		id = whatsapp.DefaultSessionID
+	}
+	client, ok := h.sessions.Get(id)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": whatsapp.ErrSessionNotFound.Error()})
+		return
+	}
+	c.Set(sessionClientKey, client)
+	c.Next()
+}
+
+// clientFor returns the client that handles a request
+func (h *MessageHandler) clientFor(c *gin.Context) *whatsapp.Client {
+	if client, ok := c.Get(sessionClientKey); ok {
+		return client.(*whatsapp.Client)
+	}
+	return h.client
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/w33ladalah/whrabbit/internal/webhook"
+)
+
+// WebhookHandler manages webhook registrations
+type WebhookHandler struct {
+	dispatcher *webhook.Dispatcher
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		dispatcher: dispatcher,
+	}
+}
+
+// WebhookRequest is the body for registering a webhook
+type WebhookRequest struct {
+	URL string `json:"url" binding:"required" example:"https://example.com/whatsapp/events"`
+	// Secret signs every delivery with HMAC-SHA256 in the X-Whrabbit-Signature header
+	Secret string `json:"secret" example:"change-me"`
+	// Events limits the event types delivered: message, receipt, presence, typing, group or connection.
+	// All events are delivered when empty.
+	Events []string `json:"events" example:"message"`
+}
+
+// ListWebhooks returns the registered webhooks
+// @Summary List webhooks
+// @Description Returns the registered webhooks. Secrets are never returned.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} webhook.Webhook
+// @Security Bearer
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	c.JSON(http.StatusOK, h.dispatcher.List())
+}
+
+// CreateWebhook registers a webhook
+// @Summary Register a webhook
+// @Description Registers a URL that receives WhatsApp events as JSON POST requests. Failed deliveries are retried with exponential backoff and end up in the dead-letter list. Webhooks registered here are kept in memory only and are gone after a restart.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body WebhookRequest true "Webhook details"
+// @Success 201 {object} webhook.Webhook
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Security Bearer
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.dispatcher.Register(req.URL, req.Secret, req.Events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// DeleteWebhook removes a webhook
+// @Summary Delete a webhook
+// @Description Stops delivering events to a webhook and drops the events still waiting for it
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]string "Webhook deleted successfully"
+// @Failure 404 {object} map[string]string "Webhook not found"
+// @Security Bearer
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.dispatcher.Remove(c.Param("id")); errors.Is(err, webhook.ErrWebhookNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "Webhook deleted successfully"})
+}
+
+// ListDeadLetters returns the events that couldn't be delivered
+// @Summary List undelivered webhook events
+// @Description Returns the events that still failed after every delivery attempt, oldest first, including events dropped because the webhook queue was full. Only the most recent 1000 are kept, in memory.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} webhook.DeadLetter
+// @Security Bearer
+// @Router /webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	c.JSON(http.StatusOK, h.dispatcher.DeadLetters())
+}
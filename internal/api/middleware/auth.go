@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,10 +11,7 @@ import (
 // APIKeyAuth middleware checks for valid API key
 func APIKeyAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		expectedKey := config.GetAPIKey()
-
-		if apiKey == "" || apiKey != expectedKey {
+		if !ValidAPIKey(c.GetHeader("X-API-Key")) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing API key"})
 			c.Abort()
 			return
@@ -22,3 +20,13 @@ func APIKeyAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ValidAPIKey reports whether key matches the configured API key.
+// An empty key or an unset API key never matches.
+func ValidAPIKey(key string) bool {
+	expectedKey := config.GetAPIKey()
+	if key == "" || expectedKey == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(expectedKey)) == 1
+}
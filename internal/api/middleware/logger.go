@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger logs requests like gin's default logger, but hides the API key that WebSocket
+// clients may pass in the token query parameter
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		var statusColor, methodColor, resetColor string
+		if param.IsOutputColor() {
+			statusColor = param.StatusCodeColor()
+			methodColor = param.MethodColor()
+			resetColor = param.ResetColor()
+		}
+		return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			statusColor, param.StatusCode, resetColor,
+			param.Latency,
+			param.ClientIP,
+			methodColor, param.Method, resetColor,
+			redactToken(param.Path),
+			param.ErrorMessage,
+		)
+	})
+}
+
+// redactToken replaces the value of the token query parameter in a request path
+func redactToken(path string) string {
+	base, rawQuery, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// Don't risk logging a key that couldn't be found
+		return base + "?[unreadable query]"
+	}
+	if !query.Has("token") {
+		return path
+	}
+	query.Set("token", "REDACTED")
+	return base + "?" + query.Encode()
+}
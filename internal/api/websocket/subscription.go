@@ -0,0 +1,110 @@
+package websocket
+
+import (
+	"sort"
+
+	"github.com/gorilla/websocket"
+)
+
+// Subscription is the set of event types and chats a client receives events for.
+// A nil list doesn't filter, while an empty list is a filter that nothing passes, such
+// as after unsubscribing from the last chat.
+type Subscription struct {
+	Events []string `json:"events"`
+	Chats  []string `json:"chats"`
+}
+
+// subscription is the filter state of a connected client. A nil set doesn't filter.
+type subscription struct {
+	events map[string]bool
+	chats  map[string]bool
+}
+
+func newSubscription() *subscription {
+	return &subscription{}
+}
+
+// matches reports whether an event of the given type in the given chat passes the filter
+func (s *subscription) matches(eventType string, chat string) bool {
+	if s.events != nil && !s.events[eventType] {
+		return false
+	}
+	if s.chats != nil && !s.chats[chat] {
+		return false
+	}
+	return true
+}
+
+// snapshot returns the subscription as sorted lists
+func (s *subscription) snapshot() Subscription {
+	return Subscription{
+		Events: sortedKeys(s.events),
+		Chats:  sortedKeys(s.chats),
+	}
+}
+
+// Subscribe adds event types and chats to a client's subscription and returns the result
+func (m *Manager) Subscribe(conn *websocket.Conn, events []string, chats []string) Subscription {
+	m.clientsMux.Lock()
+	defer m.clientsMux.Unlock()
+
+	c, ok := m.clients[conn]
+	if !ok {
+		return Subscription{}
+	}
+	sub := c.sub
+	sub.events = addKeys(sub.events, events)
+	sub.chats = addKeys(sub.chats, chats)
+	return sub.snapshot()
+}
+
+// Unsubscribe removes event types and chats from a client's subscription and returns
+// the result. Without any event types or chats the whole subscription is cleared, which
+// is the only way back to receiving every event.
+func (m *Manager) Unsubscribe(conn *websocket.Conn, events []string, chats []string) Subscription {
+	m.clientsMux.Lock()
+	defer m.clientsMux.Unlock()
+
+	c, ok := m.clients[conn]
+	if !ok {
+		return Subscription{}
+	}
+	sub := c.sub
+	if len(events) == 0 && len(chats) == 0 {
+		*sub = *newSubscription()
+	}
+	for _, eventType := range events {
+		delete(sub.events, eventType)
+	}
+	for _, chat := range chats {
+		delete(sub.chats, chat)
+	}
+	return sub.snapshot()
+}
+
+// addKeys adds keys to a set, creating it when it is nil and there are keys to add
+func addKeys(set map[string]bool, keys []string) map[string]bool {
+	if len(keys) == 0 {
+		return set
+	}
+	if set == nil {
+		set = make(map[string]bool)
+	}
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// sortedKeys returns the keys of a set in order, or nil for a nil set
+func sortedKeys(set map[string]bool) []string {
+	if set == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,116 +1,261 @@
 package websocket
 
 import (
+	"errors"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/w33ladalah/whrabbit/internal/config"
+	"github.com/w33ladalah/whrabbit/internal/qr"
 )
 
+// Connection states sent in status frames
+const (
+	StateConnected    = "connected"
+	StateDisconnected = "disconnected"
+	StateReconnecting = "reconnecting"
+	StateLoggedOut    = "logged_out"
+	StateReplaced     = "replaced"
+	StateBanned       = "temporarily_banned"
+	StateOutdated     = "client_outdated"
+	StateQRTimeout    = "qr_timeout"
+)
+
+// clientQueueSize is how many frames may wait for a client. A client that falls further
+// behind is disconnected, so a slow browser never holds up WhatsApp events.
+const clientQueueSize = 64
+
+// writeTimeout bounds the write of a single frame
+const writeTimeout = 10 * time.Second
+
 type Manager struct {
-	clients     map[*websocket.Conn]bool
-	clientsMux  sync.RWMutex
-	latestQR    string
-	qrMux       sync.RWMutex
-	isConnected bool
-	statusMux   sync.RWMutex
+	clients    map[*websocket.Conn]*client
+	clientsMux sync.RWMutex
+	latestQR   string
+	// latestQRImage is latestQR rendered as a PNG data URI
+	latestQRImage string
+	qrMux         sync.RWMutex
+	// state and status are the last connection state and message sent to clients
+	state     string
+	status    string
+	statusMux sync.RWMutex
+}
+
+// client is a connection and the frames waiting to be written to it. Each client has
+// its own writer, gorilla/websocket connections allow only one writer at a time.
+type client struct {
+	conn      *websocket.Conn
+	sub       *subscription
+	send      chan interface{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// close stops the client's writer and closes its connection, which also ends its reader
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
 }
 
 func NewManager() *Manager {
 	return &Manager{
-		clients: make(map[*websocket.Conn]bool),
+		clients: make(map[*websocket.Conn]*client),
 	}
 }
 
 func (m *Manager) AddClient(conn *websocket.Conn) {
+	c := &client{
+		conn: conn,
+		sub:  newSubscription(),
+		send: make(chan interface{}, clientQueueSize),
+		done: make(chan struct{}),
+	}
+	go m.writeLoop(c)
+
+	// Queue the first frames before any broadcast can reach the client
 	m.clientsMux.Lock()
-	m.clients[conn] = true
-	m.clientsMux.Unlock()
+	defer m.clientsMux.Unlock()
+	m.clients[conn] = c
 
 	// Always send the latest QR code first
 	m.qrMux.RLock()
 	if m.latestQR != "" {
-		err := conn.WriteJSON(map[string]string{
-			"type": "qr",
-			"code": m.latestQR,
-		})
-		if err != nil {
-			log.Printf("Error sending QR code to new client: %v", err)
-		}
+		m.queue(c, qrFrame(m.latestQR, m.latestQRImage))
 	}
 	m.qrMux.RUnlock()
 
 	// Then send connection status
 	m.statusMux.RLock()
-	if m.isConnected {
-		err := conn.WriteJSON(map[string]string{
-			"type":   "status",
-			"status": "WhatsApp already connected!",
-		})
-		if err != nil {
-			log.Printf("Error sending connection status to new client: %v", err)
-		}
+	if m.state != "" {
+		m.queue(c, statusFrame(m.state, m.status))
 	}
 	m.statusMux.RUnlock()
 }
 
 func (m *Manager) RemoveClient(conn *websocket.Conn) {
 	m.clientsMux.Lock()
-	defer m.clientsMux.Unlock()
+	c, ok := m.clients[conn]
 	delete(m.clients, conn)
-	conn.Close()
+	m.clientsMux.Unlock()
+	if ok {
+		c.close()
+	} else {
+		conn.Close()
+	}
+}
+
+// Close disconnects every client, such as when the session is deleted
+func (m *Manager) Close() {
+	m.clientsMux.Lock()
+	clients := m.clients
+	m.clients = make(map[*websocket.Conn]*client)
+	m.clientsMux.Unlock()
+
+	for conn, c := range clients {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "session deleted"), time.Now().Add(time.Second))
+		c.close()
+	}
+}
+
+// queue adds a frame to a client's queue without waiting. A client whose queue is full
+// is disconnected. The caller must hold clientsMux.
+func (m *Manager) queue(c *client, frame interface{}) {
+	select {
+	case <-c.done:
+		// Closed and waiting to be removed
+	case c.send <- frame:
+	default:
+		log.Printf("WebSocket client is too slow, disconnecting it")
+		c.close()
+	}
+}
+
+// writeLoop writes the queued frames of a client until it is closed, then removes it
+func (m *Manager) writeLoop(c *client) {
+	defer m.RemoveClient(c.conn)
+	for {
+		select {
+		case <-c.done:
+			return
+		case frame := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteJSON(frame); err != nil {
+				log.Printf("Error sending frame to client: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// broadcast queues a frame for every client
+func (m *Manager) broadcast(frame interface{}) {
+	m.clientsMux.RLock()
+	defer m.clientsMux.RUnlock()
+	for _, c := range m.clients {
+		m.queue(c, frame)
+	}
 }
 
 func (m *Manager) BroadcastQR(qrCode string) {
+	// Render the QR code once for every client, they can still draw it from the code
+	// if that fails
+	image, err := qr.PNGDataURI(qrCode, config.GetQRSize())
+	if err != nil {
+		log.Printf("Error rendering QR code: %v", err)
+	}
+
 	// Store the latest QR code
 	m.qrMux.Lock()
 	m.latestQR = qrCode
+	m.latestQRImage = image
 	m.qrMux.Unlock()
 
 	// Reset connection state when new QR code is generated
 	m.statusMux.Lock()
-	m.isConnected = false
+	m.state, m.status = "", ""
 	m.statusMux.Unlock()
 
-	m.clientsMux.RLock()
-	defer m.clientsMux.RUnlock()
+	m.broadcast(qrFrame(qrCode, image))
+}
 
-	for client := range m.clients {
-		err := client.WriteJSON(map[string]string{
-			"type": "qr",
-			"code": qrCode,
-		})
-		if err != nil {
-			log.Printf("Error sending QR code to client: %v", err)
-			client.Close()
-		}
+// qrFrame builds a qr frame, image is a PNG data URI and left out when empty
+func qrFrame(code, image string) map[string]string {
+	frame := map[string]string{
+		"type": "qr",
+		"code": code,
 	}
+	if image != "" {
+		frame["image"] = image
+	}
+	return frame
+}
+
+// LatestQR returns the last QR code sent to clients, or an empty string
+func (m *Manager) LatestQR() string {
+	m.qrMux.RLock()
+	defer m.qrMux.RUnlock()
+	return m.latestQR
+}
+
+// BroadcastPairCode sends the linking code of a phone number pairing to all clients
+func (m *Manager) BroadcastPairCode(code string) {
+	m.broadcast(map[string]string{
+		"type": "pair_code",
+		"code": code,
+	})
 }
 
-func (m *Manager) BroadcastConnectionStatus(status string) {
+// BroadcastConnectionStatus sends a status frame with one of the State constants and a
+// message for people to all clients. New clients get the last status when they connect.
+func (m *Manager) BroadcastConnectionStatus(state string, status string) {
 	m.statusMux.Lock()
-	if status == "WhatsApp disconnected" {
-		m.isConnected = false
-		// Clear the latest QR code when disconnected
-		m.qrMux.Lock()
-		m.latestQR = ""
-		m.qrMux.Unlock()
-	} else {
-		m.isConnected = status == "WhatsApp connected successfully!" || status == "WhatsApp already connected!"
-	}
+	m.state, m.status = state, status
 	m.statusMux.Unlock()
 
+	// Any change of connection state invalidates the latest QR code
+	m.qrMux.Lock()
+	m.latestQR = ""
+	m.latestQRImage = ""
+	m.qrMux.Unlock()
+
+	m.broadcast(statusFrame(state, status))
+}
+
+// statusFrame builds a status frame
+func statusFrame(state, status string) map[string]string {
+	return map[string]string{
+		"type":   "status",
+		"state":  state,
+		"status": status,
+	}
+}
+
+// BroadcastEvent sends a WhatsApp event frame to the clients subscribed to its type and
+// chat. The frame is encoded as JSON and is expected to carry its own "type" field.
+func (m *Manager) BroadcastEvent(eventType string, chat string, frame interface{}) {
 	m.clientsMux.RLock()
 	defer m.clientsMux.RUnlock()
 
-	for client := range m.clients {
-		err := client.WriteJSON(map[string]string{
-			"type":   "status",
-			"status": status,
-		})
-		if err != nil {
-			log.Printf("Error sending status to client: %v", err)
-			client.Close()
+	for _, c := range m.clients {
+		if c.sub.matches(eventType, chat) {
+			m.queue(c, frame)
 		}
 	}
 }
+
+// SendJSON sends a frame to a single client
+func (m *Manager) SendJSON(conn *websocket.Conn, frame interface{}) error {
+	m.clientsMux.RLock()
+	defer m.clientsMux.RUnlock()
+
+	c, ok := m.clients[conn]
+	if !ok {
+		return errors.New("client is not connected")
+	}
+	m.queue(c, frame)
+	return nil
+}
@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 var (
@@ -59,3 +62,90 @@ func GetAppEnv() string {
 	}
 	return os.Getenv("APP_ENV")
 }
+
+func GetMediaMaxSize() int64 {
+	size, err := strconv.ParseInt(os.Getenv("MEDIA_MAX_SIZE"), 10, 64)
+	if err != nil || size <= 0 {
+		size = 64 << 20 // Default 64 MiB
+	}
+	return size
+}
+
+func GetMediaFetchTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("MEDIA_FETCH_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		timeout = 30 * time.Second // Default timeout
+	}
+	return timeout
+}
+
+func GetLinkPreviewTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("LINK_PREVIEW_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		timeout = 5 * time.Second // Default timeout
+	}
+	return timeout
+}
+
+func GetLinkPreviewMaxSize() int64 {
+	size, err := strconv.ParseInt(os.Getenv("LINK_PREVIEW_MAX_SIZE"), 10, 64)
+	if err != nil || size <= 0 {
+		size = 2 << 20 // Default 2 MiB
+	}
+	return size
+}
+
+func GetLinkPreviewAllowedHosts() []string {
+	return splitList(os.Getenv("LINK_PREVIEW_ALLOWED_HOSTS"))
+}
+
+func GetLinkPreviewDeniedHosts() []string {
+	return splitList(os.Getenv("LINK_PREVIEW_DENIED_HOSTS"))
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func GetWebhookURLs() []string {
+	return splitList(os.Getenv("WEBHOOK_URLS"))
+}
+
+func GetWebhookSecret() string {
+	return os.Getenv("WEBHOOK_SECRET")
+}
+
+func GetWebhookMaxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		attempts = 5 // Default attempts
+	}
+	return attempts
+}
+
+func GetWebhookTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		timeout = 10 * time.Second // Default timeout
+	}
+	return timeout
+}
+
+func GetWSAllowedOrigins() []string {
+	return splitList(os.Getenv("WS_ALLOWED_ORIGINS"))
+}
+
+func GetQRSize() int {
+	size, err := strconv.Atoi(os.Getenv("QR_SIZE"))
+	if err != nil || size <= 0 {
+		size = 256 // Default size in pixels
+	}
+	return size
+}
@@ -0,0 +1,67 @@
+package qr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// MinSize and MaxSize bound the width and height in pixels of rendered QR codes
+const (
+	MinSize = 64
+	MaxSize = 2048
+)
+
+// PNG renders a QR code as a square PNG image of the given size in pixels
+func PNG(code string, size int) ([]byte, error) {
+	qr, err := qrcode.New(code, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding QR code: %v", err)
+	}
+	png, err := qr.PNG(clampSize(size))
+	if err != nil {
+		return nil, fmt.Errorf("error rendering QR code: %v", err)
+	}
+	return png, nil
+}
+
+// SVG renders a QR code as a square SVG image of the given size in pixels
+func SVG(code string, size int) ([]byte, error) {
+	qr, err := qrcode.New(code, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding QR code: %v", err)
+	}
+
+	// Draw every dark module as a 1x1 square and let the viewBox scale them
+	bitmap := qr.Bitmap()
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	size = clampSize(size)
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="%s"/></svg>`,
+		size, size, len(bitmap), len(bitmap), path.String())
+	return []byte(svg), nil
+}
+
+// PNGDataURI renders a QR code as a PNG data URI for use in an img src
+func PNGDataURI(code string, size int) (string, error) {
+	png, err := PNG(code, size)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
+
+// clampSize limits a size to MinSize and MaxSize
+func clampSize(size int) int {
+	return min(max(size, MinSize), MaxSize)
+}
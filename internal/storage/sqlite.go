@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS messages (
+	session   TEXT    NOT NULL,
+	chat      TEXT    NOT NULL,
+	id        TEXT    NOT NULL,
+	sender    TEXT    NOT NULL,
+	from_me   BOOLEAN NOT NULL,
+	type      TEXT    NOT NULL,
+	text      TEXT    NOT NULL DEFAULT '',
+	timestamp INTEGER NOT NULL,
+	stored_at INTEGER NOT NULL,
+	raw       BLOB,
+	PRIMARY KEY (session, chat, id)
+);
+CREATE INDEX IF NOT EXISTS messages_session_chat_timestamp ON messages (session, chat, timestamp DESC, id DESC);
+CREATE INDEX IF NOT EXISTS messages_session_id ON messages (session, id);
+`
+
+// legacySession is the session given to messages stored before sessions existed,
+// the ID the server uses for its first account
+const legacySession = "default"
+
+// sqliteMigration moves messages from the table without sessions into the current one
+const sqliteMigration = `
+DROP INDEX IF EXISTS messages_chat_timestamp;
+ALTER TABLE messages RENAME TO messages_legacy;
+` + sqliteSchema + `
+INSERT INTO messages (session, chat, id, sender, from_me, type, text, timestamp, stored_at, raw)
+	SELECT ?, chat, id, sender, from_me, type, text, timestamp, stored_at, raw FROM messages_legacy;
+DROP TABLE messages_legacy;
+`
+
+// SQLiteMessageStore is a MessageStore backed by a SQLite database
+type SQLiteMessageStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteMessageStore opens or creates the SQLite message database at dbPath
+func NewSQLiteMessageStore(dbPath string) (*SQLiteMessageStore, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000", dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("error opening message database: %v", err)
+	}
+
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating message tables: %v", err)
+	}
+
+	return &SQLiteMessageStore{db: db}, nil
+}
+
+// migrateSQLite upgrades a messages table created before sessions existed
+func migrateSQLite(db *sql.DB) error {
+	var legacy bool
+	err := db.QueryRow(`
+		SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'messages'
+		AND NOT EXISTS (SELECT 1 FROM pragma_table_info('messages') WHERE name = 'session')`,
+	).Scan(&legacy)
+	if err != nil {
+		return fmt.Errorf("error inspecting message tables: %v", err)
+	}
+	if !legacy {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error migrating message tables: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(sqliteMigration, legacySession); err != nil {
+		return fmt.Errorf("error migrating message tables: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error migrating message tables: %v", err)
+	}
+	return nil
+}
+
+// SaveMessage stores a message, ignoring messages that are already stored
+func (s *SQLiteMessageStore) SaveMessage(ctx context.Context, msg *Message) error {
+	storedAt := msg.StoredAt
+	if storedAt.IsZero() {
+		storedAt = time.Now()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO messages (session, chat, id, sender, from_me, type, text, timestamp, stored_at, raw)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (session, chat, id) DO NOTHING`,
+		msg.Session, msg.Chat, msg.ID, msg.Sender, msg.FromMe, msg.Type, msg.Text,
+		msg.Timestamp.UnixMilli(), storedAt.UnixMilli(), msg.Raw,
+	)
+	if err != nil {
+		return fmt.Errorf("error saving message: %v", err)
+	}
+	return nil
+}
+
+// GetMessages returns the messages of a session's chat matching the query, newest first
+func (s *SQLiteMessageStore) GetMessages(ctx context.Context, session, chat string, query MessageQuery) (*MessagePage, error) {
+	columns := "session, chat, id, sender, from_me, type, text, timestamp, stored_at"
+	if query.IncludeRaw {
+		columns += ", raw"
+	}
+
+	conditions := []string{"session = ?", "chat = ?"}
+	args := []interface{}{session, chat}
+	if query.Cursor != "" {
+		ts, id, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "(timestamp < ? OR (timestamp = ? AND id < ?))")
+		args = append(args, ts.UnixMilli(), ts.UnixMilli(), id)
+	}
+	if query.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, query.Type)
+	}
+	if query.Sender != "" {
+		conditions = append(conditions, "sender = ?")
+		args = append(args, query.Sender)
+	}
+	if query.FromMe != nil {
+		conditions = append(conditions, "from_me = ?")
+		args = append(args, *query.FromMe)
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, query.Since.UnixMilli())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "timestamp < ?")
+		args = append(args, query.Until.UnixMilli())
+	}
+
+	// Fetch one extra row to know whether there is another page
+	limit := query.pageSize()
+	args = append(args, limit+1)
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM messages WHERE %s ORDER BY timestamp DESC, id DESC LIMIT ?",
+		columns, strings.Join(conditions, " AND "),
+	), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying messages: %v", err)
+	}
+	defer rows.Close()
+
+	page := &MessagePage{Messages: []Message{}}
+	for rows.Next() {
+		var msg Message
+		var timestamp, storedAt int64
+		dest := []interface{}{&msg.Session, &msg.Chat, &msg.ID, &msg.Sender, &msg.FromMe, &msg.Type, &msg.Text, &timestamp, &storedAt}
+		if query.IncludeRaw {
+			dest = append(dest, &msg.Raw)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("error reading message: %v", err)
+		}
+		msg.Timestamp = time.UnixMilli(timestamp).UTC()
+		msg.StoredAt = time.UnixMilli(storedAt).UTC()
+		page.Messages = append(page.Messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading messages: %v", err)
+	}
+
+	if len(page.Messages) > limit {
+		page.Messages = page.Messages[:limit]
+		page.NextCursor = encodeCursor(page.Messages[limit-1])
+	}
+	return page, nil
+}
+
+// GetMessage returns the newest message of a session with the given ID in any chat
+func (s *SQLiteMessageStore) GetMessage(ctx context.Context, session, id string) (*Message, error) {
+	var msg Message
+	var timestamp, storedAt int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT session, chat, id, sender, from_me, type, text, timestamp, stored_at FROM messages
+		WHERE session = ? AND id = ? ORDER BY timestamp DESC LIMIT 1`,
+		session, id,
+	).Scan(&msg.Session, &msg.Chat, &msg.ID, &msg.Sender, &msg.FromMe, &msg.Type, &msg.Text, &timestamp, &storedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading message: %v", err)
+	}
+	msg.Timestamp = time.UnixMilli(timestamp).UTC()
+	msg.StoredAt = time.UnixMilli(storedAt).UTC()
+	return &msg, nil
+}
+
+// Close closes the database
+func (s *SQLiteMessageStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPageSize is the number of messages returned when a query has no limit
+const DefaultPageSize = 50
+
+// MaxPageSize is the largest number of messages returned by a single query
+const MaxPageSize = 200
+
+// ErrInvalidCursor is returned when a pagination cursor can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrMessageNotFound is returned when a message isn't stored
+var ErrMessageNotFound = errors.New("message not found")
+
+// Message is an inbound or outbound WhatsApp message
+type Message struct {
+	ID string `json:"id" example:"3EB0C431C26A1916E5F6"`
+	// Session is the ID of the session that sent or received the message
+	Session string `json:"session" example:"default"`
+	Chat    string `json:"chat" example:"1234567890@s.whatsapp.net"`
+	Sender  string `json:"sender" example:"1234567890@s.whatsapp.net"`
+	FromMe  bool   `json:"from_me"`
+	// Type is the kind of content, such as text, image, reaction or poll
+	Type string `json:"type" example:"text"`
+	// Text is the message text or media caption, if any
+	Text string `json:"text,omitempty" example:"Hello, World!"`
+	// Timestamp is when the message was sent according to WhatsApp
+	Timestamp time.Time `json:"timestamp"`
+	// StoredAt is when the message was recorded by this server
+	StoredAt time.Time `json:"stored_at"`
+	// Raw is the protobuf encoded message, only loaded when requested
+	Raw []byte `json:"raw,omitempty" swaggertype:"string" format:"base64"`
+}
+
+// MessageQuery filters and paginates the messages of a chat
+type MessageQuery struct {
+	// Cursor continues from the end of a previous page
+	Cursor string
+	// Limit defaults to DefaultPageSize and is capped at MaxPageSize
+	Limit  int
+	Type   string
+	Sender string
+	FromMe *bool
+	// Since and Until bound the message timestamp, zero values are ignored
+	Since time.Time
+	Until time.Time
+	// IncludeRaw loads the raw protobuf of each message
+	IncludeRaw bool
+}
+
+// MessagePage is a page of messages, newest first
+type MessagePage struct {
+	Messages []Message `json:"messages"`
+	// NextCursor fetches the next (older) page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// MessageStore persists messages. Implementations must be safe for concurrent use.
+type MessageStore interface {
+	// SaveMessage stores a message, ignoring messages that are already stored
+	SaveMessage(ctx context.Context, msg *Message) error
+	// GetMessages returns the messages of a session's chat matching the query, newest first
+	GetMessages(ctx context.Context, session, chat string, query MessageQuery) (*MessagePage, error)
+	// GetMessage returns the newest message of a session with the given ID in any chat
+	GetMessage(ctx context.Context, session, id string) (*Message, error)
+	Close() error
+}
+
+// pageSize returns the effective limit of a query
+func (q MessageQuery) pageSize() int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultPageSize
+	case q.Limit > MaxPageSize:
+		return MaxPageSize
+	default:
+		return q.Limit
+	}
+}
+
+// encodeCursor builds an opaque cursor pointing just past the given message
+func encodeCursor(msg Message) string {
+	raw := strconv.FormatInt(msg.Timestamp.UnixMilli(), 10) + ":" + msg.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor returns the timestamp and ID encoded in a cursor
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	millis, id, found := strings.Cut(string(raw), ":")
+	if !found || id == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	ts, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return time.UnixMilli(ts), id, nil
+}
@@ -0,0 +1,286 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/w33ladalah/whrabbit/internal/whatsapp"
+)
+
+// Headers sent with every delivery
+const (
+	SignatureHeader = "X-Whrabbit-Signature"
+	EventHeader     = "X-Whrabbit-Event"
+	DeliveryHeader  = "X-Whrabbit-Delivery"
+)
+
+// maxDeadLetters caps the dead-letter list, dropping the oldest entries first
+const maxDeadLetters = 1000
+
+// initialBackoff is the wait before the first retry, doubled after every attempt
+const initialBackoff = time.Second
+
+// queueSize is how many events may wait for a webhook. Events that don't fit, such as
+// during a burst while the receiver is down, go to the dead-letter list right away.
+const queueSize = 1000
+
+// workers is how many deliveries to a webhook run at the same time
+const workers = 4
+
+// ErrWebhookNotFound is returned when removing a webhook that doesn't exist
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// Webhook is a URL that receives WhatsApp events
+type Webhook struct {
+	ID  string `json:"id" example:"4f1d2c3b5a697887"`
+	URL string `json:"url" example:"https://example.com/whatsapp/events"`
+	// Secret signs deliveries with HMAC-SHA256, it is never returned by the API
+	Secret string `json:"-"`
+	// Events limits the event types delivered, all events are delivered when empty
+	Events    []string  `json:"events,omitempty" example:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeadLetter is an event that couldn't be delivered after every attempt
+type DeadLetter struct {
+	WebhookID string         `json:"webhook_id"`
+	URL       string         `json:"url"`
+	Event     whatsapp.Event `json:"event"`
+	Attempts  int            `json:"attempts"`
+	LastError string         `json:"last_error"`
+	FailedAt  time.Time      `json:"failed_at"`
+}
+
+// delivery is an event waiting to be posted to a webhook
+type delivery struct {
+	evt  whatsapp.Event
+	body []byte
+}
+
+// endpoint is a registered webhook and the queue its workers deliver from
+type endpoint struct {
+	webhook Webhook
+	queue   chan delivery
+	// done is closed when the webhook is removed, stopping its workers
+	done chan struct{}
+}
+
+// Dispatcher delivers WhatsApp events to the registered webhooks. Every webhook has a
+// bounded queue and a few workers, so a receiver that is down can't pile up goroutines.
+type Dispatcher struct {
+	webhooks    map[string]*endpoint
+	webhooksMux sync.RWMutex
+	deadLetters []DeadLetter
+	deadMux     sync.RWMutex
+	httpClient  *http.Client
+	maxAttempts int
+}
+
+// NewDispatcher creates a dispatcher with no webhooks
+func NewDispatcher(timeout time.Duration, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		webhooks:    make(map[string]*endpoint),
+		httpClient:  &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Register adds a webhook. An empty events list subscribes to all event types.
+func (d *Dispatcher) Register(rawURL string, secret string, events []string) (*Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL: %s", rawURL)
+	}
+	for _, eventType := range events {
+		if !slices.Contains(whatsapp.EventTypes, eventType) {
+			return nil, fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+
+	webhook := &Webhook{
+		ID:        newID(),
+		URL:       parsed.String(),
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	ep := &endpoint{
+		webhook: *webhook,
+		queue:   make(chan delivery, queueSize),
+		done:    make(chan struct{}),
+	}
+	for range workers {
+		go d.work(ep)
+	}
+
+	d.webhooksMux.Lock()
+	d.webhooks[webhook.ID] = ep
+	d.webhooksMux.Unlock()
+	return webhook, nil
+}
+
+// Remove deletes a webhook. Events still waiting for it are dropped.
+func (d *Dispatcher) Remove(id string) error {
+	d.webhooksMux.Lock()
+	defer d.webhooksMux.Unlock()
+	ep, ok := d.webhooks[id]
+	if !ok {
+		return ErrWebhookNotFound
+	}
+	delete(d.webhooks, id)
+	close(ep.done)
+	return nil
+}
+
+// List returns the registered webhooks, oldest first
+func (d *Dispatcher) List() []Webhook {
+	d.webhooksMux.RLock()
+	webhooks := make([]Webhook, 0, len(d.webhooks))
+	for _, ep := range d.webhooks {
+		webhooks = append(webhooks, ep.webhook)
+	}
+	d.webhooksMux.RUnlock()
+
+	slices.SortFunc(webhooks, func(a, b Webhook) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return webhooks
+}
+
+// DeadLetters returns the events that couldn't be delivered, oldest first
+func (d *Dispatcher) DeadLetters() []DeadLetter {
+	d.deadMux.RLock()
+	defer d.deadMux.RUnlock()
+	return slices.Clone(d.deadLetters)
+}
+
+// HandleEvent implements whatsapp.EventSink. Events are queued for each subscribed
+// webhook so a slow endpoint never holds up the WhatsApp connection.
+func (d *Dispatcher) HandleEvent(evt whatsapp.Event) {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		log.Printf("Error encoding %s event for webhooks: %v", evt.Type, err)
+		return
+	}
+
+	d.webhooksMux.RLock()
+	defer d.webhooksMux.RUnlock()
+	for _, ep := range d.webhooks {
+		if len(ep.webhook.Events) > 0 && !slices.Contains(ep.webhook.Events, evt.Type) {
+			continue
+		}
+		select {
+		case ep.queue <- delivery{evt: evt, body: body}:
+		default:
+			log.Printf("Webhook %s queue is full, dropping event %s", ep.webhook.URL, evt.ID)
+			d.addDeadLetter(ep.webhook, evt, 0, errors.New("delivery queue is full"))
+		}
+	}
+}
+
+// work delivers the queued events of a webhook until it is removed
+func (d *Dispatcher) work(ep *endpoint) {
+	for {
+		select {
+		case <-ep.done:
+			return
+		case next := <-ep.queue:
+			d.deliver(ep, next)
+		}
+	}
+}
+
+// deliver posts an event to a webhook, retrying with exponential backoff. Events that
+// still fail after the last attempt are added to the dead-letter list.
+func (d *Dispatcher) deliver(ep *endpoint, next delivery) {
+	backoff := initialBackoff
+	var lastErr error
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if lastErr = d.post(ep.webhook, next.evt, next.body); lastErr == nil {
+			return
+		}
+		log.Printf("Webhook %s delivery of event %s failed (attempt %d/%d): %v", ep.webhook.URL, next.evt.ID, attempt, d.maxAttempts, lastErr)
+		if attempt < d.maxAttempts {
+			select {
+			case <-ep.done:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+
+	d.addDeadLetter(ep.webhook, next.evt, d.maxAttempts, lastErr)
+}
+
+// addDeadLetter records an event that couldn't be delivered
+func (d *Dispatcher) addDeadLetter(webhook Webhook, evt whatsapp.Event, attempts int, err error) {
+	d.deadMux.Lock()
+	defer d.deadMux.Unlock()
+	d.deadLetters = append(d.deadLetters, DeadLetter{
+		WebhookID: webhook.ID,
+		URL:       webhook.URL,
+		Event:     evt,
+		Attempts:  attempts,
+		LastError: err.Error(),
+		FailedAt:  time.Now().UTC(),
+	})
+	if len(d.deadLetters) > maxDeadLetters {
+		d.deadLetters = slices.Delete(d.deadLetters, 0, len(d.deadLetters)-maxDeadLetters)
+	}
+}
+
+// post makes a single delivery attempt, treating any non-2xx status as a failure
+func (d *Dispatcher) post(webhook Webhook, evt whatsapp.Event, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, evt.Type)
+	req.Header.Set(DeliveryHeader, evt.ID)
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, body))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body using secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newID returns a random webhook ID
+func newID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
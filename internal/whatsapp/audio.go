@@ -0,0 +1,184 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// opusMimeType is the MIME type WhatsApp expects for voice notes
+const opusMimeType = "audio/ogg; codecs=opus"
+
+// waveformSamples is the number of bars WhatsApp draws in a voice note bubble
+const waveformSamples = 64
+
+// SendAudio sends an audio message to a WhatsApp number.
+// When ptt is true the audio is sent as a push-to-talk voice note, converting
+// it to OGG/Opus with ffmpeg first if needed.
+func (c *Client) SendAudio(to string, audio io.Reader, ptt bool, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	// Read audio data
+	audioData, err := io.ReadAll(audio)
+	if err != nil {
+		return nil, fmt.Errorf("error reading audio: %v", err)
+	}
+
+	mimeType := detectMimeType(audioData)
+	if !strings.HasPrefix(mimeType, "audio/") && mimeType != "application/ogg" && mimeType != "video/mp4" {
+		return nil, fmt.Errorf("unsupported audio type: %s", mimeType)
+	}
+
+	opus, isOpus := parseOggOpus(audioData)
+	if ptt && !isOpus {
+		// Voice notes only play back properly as OGG/Opus
+		audioData, err = runFFmpeg(audioData, "-vn", "-c:a", "libopus", "-b:a", "32k", "-ac", "1", "-f", "ogg", "pipe:1")
+		if err != nil {
+			return nil, fmt.Errorf("error converting audio to OGG/Opus: %v", err)
+		}
+		opus, isOpus = parseOggOpus(audioData)
+	}
+
+	var seconds uint32
+	var waveform []byte
+	if isOpus {
+		mimeType = opusMimeType
+		seconds = opus.Seconds
+		waveform = opus.Waveform
+	} else if mimeType == "audio/mp4" || mimeType == "video/mp4" || mimeType == "audio/x-m4a" {
+		mimeType = "audio/mp4"
+		seconds = probeMP4(audioData).Seconds
+	} else {
+		log.Printf("Could not determine duration of %s audio", mimeType)
+	}
+
+	// Upload audio to WhatsApp
+	uploaded, err := c.uploadMedia(audioData, whatsmeow.MediaAudio)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading audio: %v", err)
+	}
+
+	msg := &waProto.Message{
+		AudioMessage: &waProto.AudioMessage{
+			URL:           &uploaded.URL,
+			Mimetype:      proto.String(mimeType),
+			FileSHA256:    uploaded.FileSHA256,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileLength:    &uploaded.FileLength,
+			MediaKey:      uploaded.MediaKey,
+			DirectPath:    &uploaded.DirectPath,
+			Seconds:       proto.Uint32(seconds),
+			PTT:           proto.Bool(ptt),
+			Waveform:      waveform,
+		},
+	}
+
+	return c.sendMessage(recipient, msg, opts)
+}
+
+// opusInfo holds the metadata read from an OGG/Opus stream
+type opusInfo struct {
+	Seconds  uint32
+	Waveform []byte
+}
+
+// parseOggOpus reads the duration and an approximate waveform from an OGG/Opus
+// stream. Opus packets can't be decoded without libopus, so the waveform is
+// derived from the packet sizes, which track loudness closely for VBR streams.
+// Returns false if the data is not OGG/Opus.
+func parseOggOpus(data []byte) (opusInfo, bool) {
+	var info opusInfo
+	var packets [][]byte
+	var current []byte
+	var lastGranule uint64
+
+	for len(data) >= 27 && bytes.HasPrefix(data, []byte("OggS")) {
+		granule := binary.LittleEndian.Uint64(data[6:14])
+		segments := int(data[26])
+		if len(data) < 27+segments {
+			break
+		}
+		lacing := data[27 : 27+segments]
+		body := data[27+segments:]
+
+		offset := 0
+		for _, size := range lacing {
+			if offset+int(size) > len(body) {
+				return info, false
+			}
+			current = append(current, body[offset:offset+int(size)]...)
+			offset += int(size)
+			// A lacing value below 255 terminates the packet
+			if size < 255 {
+				packets = append(packets, current)
+				current = nil
+			}
+		}
+		// Pages that only continue a packet have a granule position of -1
+		if granule != ^uint64(0) {
+			lastGranule = granule
+		}
+		data = body[offset:]
+	}
+
+	if len(packets) < 2 || !bytes.HasPrefix(packets[0], []byte("OpusHead")) || len(packets[0]) < 12 {
+		return info, false
+	}
+
+	// Granule positions are always in 48kHz samples, offset by the pre-skip
+	preSkip := uint64(binary.LittleEndian.Uint16(packets[0][10:12]))
+	if lastGranule > preSkip {
+		info.Seconds = uint32((lastGranule - preSkip + 24000) / 48000)
+	}
+
+	// Skip the OpusHead and OpusTags header packets
+	info.Waveform = packetWaveform(packets[2:])
+	return info, true
+}
+
+// packetWaveform buckets the packet sizes into waveformSamples values scaled to 0-100
+func packetWaveform(packets [][]byte) []byte {
+	waveform := make([]byte, waveformSamples)
+	if len(packets) == 0 {
+		return waveform
+	}
+
+	levels := make([]float64, waveformSamples)
+	var peak float64
+	for i := range levels {
+		start := i * len(packets) / waveformSamples
+		end := (i + 1) * len(packets) / waveformSamples
+		if end <= start {
+			end = start + 1
+		}
+		if end > len(packets) {
+			end = len(packets)
+		}
+		var total int
+		for _, packet := range packets[start:end] {
+			total += len(packet)
+		}
+		levels[i] = float64(total) / float64(end-start)
+		if levels[i] > peak {
+			peak = levels[i]
+		}
+	}
+
+	if peak == 0 {
+		return waveform
+	}
+	for i, level := range levels {
+		waveform[i] = byte(level / peak * 100)
+	}
+	return waveform
+}
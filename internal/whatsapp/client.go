@@ -6,10 +6,14 @@ import (
 	"io"
 	"log"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 	"github.com/w33ladalah/whrabbit/internal/api/websocket"
 	"github.com/w33ladalah/whrabbit/internal/config"
+	"github.com/w33ladalah/whrabbit/internal/storage"
 	"go.mau.fi/whatsmeow"
 	waProto "go.mau.fi/whatsmeow/binary/proto"
 	"go.mau.fi/whatsmeow/store"
@@ -23,11 +27,47 @@ import (
 // Client wraps the WhatsApp client with additional functionality
 type Client struct {
 	*whatsmeow.Client
+	sessionID string
 	wsManager *websocket.Manager
+	polls     *pollStore
+	sent      *sentStore
+	messages  storage.MessageStore
+	sinks     []EventSink
+	sinksMux  sync.RWMutex
+	// connectedAt is when the client last connected to WhatsApp
+	connectedAt    time.Time
+	connectedAtMux sync.RWMutex
+	// reconnectCancel stops the running reconnect loop, reconnectAttempts counts the
+	// attempts since the last successful connection
+	reconnectCancel   context.CancelFunc
+	reconnectAttempts int
+	reconnectMux      sync.Mutex
+	// loginDropped is set when the connection of a QR login drops, which ends the login
+	// like a timeout does
+	loginDropped atomic.Bool
+	// login is the QR login in progress, if any. Only one runs at a time.
+	login    *qrLogin
+	loginMux sync.Mutex
 }
 
-// NewClient creates a new WhatsApp client
+// NewClient creates a WhatsApp client for the first device in the database at dbPath.
+// Use a SessionManager to run several accounts side by side.
 func NewClient(dbPath string) (*Client, error) {
+	container, err := newContainer(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceStore, err := container.GetFirstDevice()
+	if err != nil {
+		return nil, fmt.Errorf("error getting device store: %v", err)
+	}
+
+	return newClient(DefaultSessionID, deviceStore), nil
+}
+
+// newContainer opens the whatsmeow device database at dbPath
+func newContainer(dbPath string) (*sqlstore.Container, error) {
 	container, err := sqlstore.New("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on", dbPath), waLog.Stdout("Database", "DEBUG", true))
 	if err != nil {
 		return nil, fmt.Errorf("error creating database container: %v", err)
@@ -40,15 +80,18 @@ func NewClient(dbPath string) (*Client, error) {
 		Tertiary:  proto.Uint32(0),
 	}
 
-	deviceStore, err := container.GetFirstDevice()
-	if err != nil {
-		return nil, fmt.Errorf("error getting device store: %v", err)
-	}
+	return container, nil
+}
 
-	client := whatsmeow.NewClient(deviceStore, waLog.Stdout("Client", "DEBUG", true))
+// newClient creates the client of a session around a device store
+func newClient(sessionID string, deviceStore *store.Device) *Client {
+	client := whatsmeow.NewClient(deviceStore, waLog.Stdout("Client "+sessionID, "DEBUG", true))
 	waClient := &Client{
 		Client:    client,
+		sessionID: sessionID,
 		wsManager: websocket.NewManager(),
+		polls:     newPollStore(),
+		sent:      newSentStore(),
 	}
 
 	// Add default event handler
@@ -56,101 +99,48 @@ func NewClient(dbPath string) (*Client, error) {
 		switch v := evt.(type) {
 		case *events.Message:
 			log.Printf("Received message from %s: %s", v.Info.Sender, v.Message.GetConversation())
-		case *events.Connected:
-			log.Println("WhatsApp connected successfully")
-			if waClient.wsManager != nil {
-				waClient.wsManager.BroadcastConnectionStatus("WhatsApp connected successfully!")
-			}
-		case *events.Disconnected:
-			log.Println("WhatsApp disconnected")
-			if waClient.wsManager != nil {
-				waClient.wsManager.BroadcastConnectionStatus("WhatsApp disconnected")
-				// Clear the device store to force new login
-				waClient.Store.ID = nil
-				// Trigger new QR code generation
-				go func() {
-					ctx := context.Background()
-					qrChan, _ := waClient.GetQRChannel(ctx)
-					err := waClient.Client.Connect()
-					if err != nil {
-						log.Printf("Error connecting after disconnect: %v", err)
-						return
-					}
-					for evt := range qrChan {
-						if evt.Event == "code" {
-							waClient.wsManager.BroadcastQR(evt.Code)
-							fmt.Println("New QR code after disconnect:", evt.Code)
-						}
-					}
-				}()
-			}
+			waClient.handlePollMessage(v)
+			waClient.storeMessage(v.Info.ID, v.Info.Chat, v.Info.Sender, v.Info.IsFromMe, v.Info.Timestamp, v.Message)
 		}
 	})
 
-	// Check if already logged in
-	if deviceStore.ID != nil {
-		waClient.wsManager.BroadcastConnectionStatus("WhatsApp already connected!")
-	}
+	// Reconnect after transient disconnects and log in again after a logout
+	client.EnableAutoReconnect = false
+	client.AddEventHandler(waClient.handleConnectionEvent)
+
+	// Forward typed events to the registered event sinks
+	client.AddEventHandler(waClient.emitEvent)
 
-	return waClient, nil
+	return waClient
 }
 
 // Connect connects to WhatsApp and handles QR code if needed
 func (c *Client) Connect(ctx context.Context) error {
 	if c.Store.ID == nil {
-		// No ID stored, new login
-		qrChan, _ := c.GetQRChannel(ctx)
-		err := c.Client.Connect()
-		if err != nil {
-			return fmt.Errorf("error connecting to WhatsApp: %v", err)
-		}
-		for evt := range qrChan {
-			if evt.Event == "code" {
-				// Broadcast QR code to all connected WebSocket clients
-				c.wsManager.BroadcastQR(evt.Code)
-				fmt.Println("QR code:", evt.Code)
-			} else {
-				fmt.Println("Login event:", evt.Event)
-				if evt.Event == "success" {
-					c.wsManager.BroadcastConnectionStatus("WhatsApp connected successfully!")
-				}
-			}
+		// No ID stored, new login. A login that is already running is left to finish.
+		login, started := c.beginLogin(ctx)
+		if !started {
+			return nil
 		}
-	} else {
-		// Already logged in, just connect
-		err := c.Client.Connect()
-		if err != nil {
-			return fmt.Errorf("error connecting to WhatsApp: %v", err)
-		}
-		c.wsManager.BroadcastConnectionStatus("WhatsApp already connected!")
+		return c.runLogin(login)
+	}
+
+	// Already logged in, just connect. The Connected event reports success.
+	err := c.Client.Connect()
+	if err != nil {
+		// Keep trying in the background, the network may not be up yet
+		c.startReconnect(0)
+		return fmt.Errorf("error connecting to WhatsApp: %v", err)
 	}
 	return nil
 }
 
-// Disconnect disconnects from WhatsApp and triggers QR code generation
+// Disconnect closes the connection to WhatsApp without logging out. The session is
+// kept and Connect can be called to use it again.
 func (c *Client) Disconnect() {
+	c.stopReconnect()
 	c.Client.Disconnect()
-	c.wsManager.BroadcastConnectionStatus("WhatsApp disconnected")
-
-	// Clear the device store to force new login
-	c.Store.ID = nil
-
-	// Trigger new QR code generation
-	go func() {
-		ctx := context.Background()
-		qrChan, _ := c.GetQRChannel(ctx)
-		err := c.Client.Connect()
-		if err != nil {
-			log.Printf("Error connecting after disconnect: %v", err)
-			return
-		}
-		for evt := range qrChan {
-			if evt.Event == "code" {
-				c.wsManager.BroadcastQR(evt.Code)
-				fmt.Println("New QR code after disconnect:", evt.Code)
-			}
-		}
-	}()
+	c.wsManager.BroadcastConnectionStatus(websocket.StateDisconnected, "WhatsApp disconnected")
 }
 
 // SetWebSocketManager sets the WebSocket manager for the client
@@ -158,6 +148,11 @@ func (c *Client) SetWebSocketManager(manager *websocket.Manager) {
 	c.wsManager = manager
 }
 
+// SessionID returns the ID of the session the client belongs to
+func (c *Client) SessionID() string {
+	return c.sessionID
+}
+
 // GetWebSocketManager returns the WebSocket manager for the client
 func (c *Client) GetWebSocketManager() *websocket.Manager {
 	return c.wsManager
@@ -165,6 +160,9 @@ func (c *Client) GetWebSocketManager() *websocket.Manager {
 
 // ParseJID parses a string into a JID
 func ParseJID(arg string) (types.JID, error) {
+	if arg == "" {
+		return types.JID{}, fmt.Errorf("empty JID")
+	}
 	if arg[0] == '+' {
 		arg = arg[1:]
 	}
@@ -179,53 +177,108 @@ func ParseJID(arg string) (types.JID, error) {
 	}
 }
 
-// SendText sends a text message to a WhatsApp number
-func (c *Client) SendText(to string, message string) error {
+// SendText sends a text message to a WhatsApp number.
+// Mentioned users must be tagged as @number in the message. When withPreview is set, a
+// preview card is attached for the first URL in the message when one can be fetched.
+func (c *Client) SendText(to string, message string, mentions []string, withPreview bool, opts SendOptions) (*SendResult, error) {
 	recipient, err := ParseJID(to)
 	if err != nil {
-		return fmt.Errorf("invalid recipient number: %v", err)
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	mentionedJIDs, err := parseMentions(message, mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	var preview *linkPreview
+	if withPreview {
+		if link := findURL(message); link != "" {
+			preview, err = fetchLinkPreview(link)
+			if err != nil {
+				// Send the message without a preview rather than failing it
+				log.Printf("Could not fetch link preview for %s: %v", link, err)
+			}
+		}
 	}
 
 	msg := &waProto.Message{
 		Conversation: proto.String(message),
 	}
+	if len(mentionedJIDs) > 0 || preview != nil {
+		// Mentions and previews need an extended text message
+		extended := &waProto.ExtendedTextMessage{
+			Text: proto.String(message),
+		}
+		if len(mentionedJIDs) > 0 {
+			extended.ContextInfo = &waProto.ContextInfo{
+				MentionedJID: mentionedJIDs,
+			}
+		}
+		if preview != nil {
+			extended.MatchedText = proto.String(preview.MatchedText)
+			extended.Title = proto.String(preview.Title)
+			extended.Description = proto.String(preview.Description)
+			extended.JPEGThumbnail = preview.Thumbnail
+			extended.PreviewType = waProto.ExtendedTextMessage_NONE.Enum()
+		}
+		msg = &waProto.Message{ExtendedTextMessage: extended}
+	}
 
-	_, err = c.Client.SendMessage(context.Background(), recipient, msg)
-	return err
+	return c.sendMessage(recipient, msg, opts)
 }
 
 // SendImage sends an image message to a WhatsApp number
-func (c *Client) SendImage(to string, image io.Reader) error {
+func (c *Client) SendImage(to string, image io.Reader, caption string, viewOnce bool, opts SendOptions) (*SendResult, error) {
 	recipient, err := ParseJID(to)
 	if err != nil {
-		return fmt.Errorf("invalid recipient number: %v", err)
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
 	}
 
 	// Read image data
 	imageData, err := io.ReadAll(image)
 	if err != nil {
-		return fmt.Errorf("error reading image: %v", err)
+		return nil, fmt.Errorf("error reading image: %v", err)
+	}
+
+	mimeType := detectMimeType(imageData)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, fmt.Errorf("unsupported image type: %s", mimeType)
 	}
 
 	// Upload image to WhatsApp
-	uploaded, err := c.Client.Upload(context.Background(), imageData, whatsmeow.MediaImage)
+	uploaded, err := c.uploadMedia(imageData, whatsmeow.MediaImage)
 	if err != nil {
-		return fmt.Errorf("error uploading image: %v", err)
+		return nil, fmt.Errorf("error uploading image: %v", err)
 	}
 
 	msg := &waProto.Message{
 		ImageMessage: &waProto.ImageMessage{
-			URL:        &uploaded.URL,
-			Mimetype:   proto.String("image/jpeg"),
-			Caption:    proto.String(""),
-			FileSHA256: uploaded.FileSHA256,
-			FileLength: &uploaded.FileLength,
-			MediaKey:   uploaded.MediaKey,
-			DirectPath: &uploaded.DirectPath,
-			ViewOnce:   proto.Bool(false),
+			URL:           &uploaded.URL,
+			Mimetype:      proto.String(mimeType),
+			Caption:       proto.String(caption),
+			FileSHA256:    uploaded.FileSHA256,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileLength:    &uploaded.FileLength,
+			MediaKey:      uploaded.MediaKey,
+			DirectPath:    &uploaded.DirectPath,
+			ViewOnce:      proto.Bool(viewOnce),
 		},
 	}
 
-	_, err = c.Client.SendMessage(context.Background(), recipient, msg)
-	return err
+	// WhatsApp shows the image without a preview when it can't be decoded here
+	img, err := decodeImage(imageData)
+	if err != nil {
+		log.Printf("Could not read image dimensions: %v", err)
+	} else {
+		msg.ImageMessage.Width = proto.Uint32(uint32(img.Bounds().Dx()))
+		msg.ImageMessage.Height = proto.Uint32(uint32(img.Bounds().Dy()))
+		thumbnail, err := imageThumbnail(img)
+		if err != nil {
+			log.Printf("Could not generate image thumbnail: %v", err)
+		}
+		msg.ImageMessage.JPEGThumbnail = thumbnail
+	}
+
+	return c.sendMessage(recipient, msg, opts)
 }
@@ -0,0 +1,90 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Contact is a contact card sent as a vCard
+type Contact struct {
+	Name         string   `json:"name" binding:"required" example:"Jane Doe"`
+	Phones       []string `json:"phones" example:"+6281234567890"`
+	Emails       []string `json:"emails" example:"jane@example.com"`
+	Organization string   `json:"org" example:"Example Corp"`
+}
+
+// vcardEscaper escapes the characters with special meaning in vCard values
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// SendContacts sends one or more contact cards to a WhatsApp number
+func (c *Client) SendContacts(to string, contacts []Contact, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	if len(contacts) == 0 {
+		return nil, fmt.Errorf("at least one contact is required")
+	}
+
+	cards := make([]*waProto.ContactMessage, len(contacts))
+	for i, contact := range contacts {
+		if strings.TrimSpace(contact.Name) == "" {
+			return nil, fmt.Errorf("contact %d has no name", i+1)
+		}
+		cards[i] = &waProto.ContactMessage{
+			DisplayName: proto.String(contact.Name),
+			Vcard:       proto.String(buildVCard(contact)),
+		}
+	}
+
+	msg := &waProto.Message{}
+	if len(cards) == 1 {
+		msg.ContactMessage = cards[0]
+	} else {
+		msg.ContactsArrayMessage = &waProto.ContactsArrayMessage{
+			DisplayName: proto.String(fmt.Sprintf("%d contacts", len(cards))),
+			Contacts:    cards,
+		}
+	}
+
+	return c.sendMessage(recipient, msg, opts)
+}
+
+// buildVCard renders a contact as a vCard 3.0. Phone numbers get a waid
+// parameter so WhatsApp shows the "Message" button for them.
+func buildVCard(contact Contact) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\n")
+	b.WriteString("VERSION:3.0\n")
+	fmt.Fprintf(&b, "N:;%s;;;\n", vcardEscaper.Replace(contact.Name))
+	fmt.Fprintf(&b, "FN:%s\n", vcardEscaper.Replace(contact.Name))
+	if contact.Organization != "" {
+		fmt.Fprintf(&b, "ORG:%s\n", vcardEscaper.Replace(contact.Organization))
+	}
+	for _, phone := range contact.Phones {
+		digits := phoneDigits(phone)
+		if digits == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "TEL;type=CELL;type=VOICE;waid=%s:+%s\n", digits, digits)
+	}
+	for _, email := range contact.Emails {
+		fmt.Fprintf(&b, "EMAIL;type=INTERNET:%s\n", vcardEscaper.Replace(email))
+	}
+	b.WriteString("END:VCARD")
+	return b.String()
+}
+
+// phoneDigits strips everything but digits from a phone number
+func phoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
@@ -0,0 +1,91 @@
+package whatsapp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"regexp"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// pdfPagePattern matches page objects in a PDF, excluding the /Pages tree nodes
+var pdfPagePattern = regexp.MustCompile(`/Type\s*/Page\b`)
+
+// SendDocument sends a document message to a WhatsApp number.
+// The title defaults to the file name when empty.
+func (c *Client) SendDocument(to string, document io.Reader, fileName, title, caption string, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	if fileName == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+
+	// Read document data
+	documentData, err := io.ReadAll(document)
+	if err != nil {
+		return nil, fmt.Errorf("error reading document: %v", err)
+	}
+
+	mimeType := documentMimeType(documentData, fileName)
+
+	// Upload document to WhatsApp
+	uploaded, err := c.uploadMedia(documentData, whatsmeow.MediaDocument)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading document: %v", err)
+	}
+
+	if title == "" {
+		title = fileName
+	}
+
+	msg := &waProto.Message{
+		DocumentMessage: &waProto.DocumentMessage{
+			URL:           &uploaded.URL,
+			Mimetype:      proto.String(mimeType),
+			Title:         proto.String(title),
+			FileName:      proto.String(fileName),
+			Caption:       proto.String(caption),
+			FileSHA256:    uploaded.FileSHA256,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileLength:    &uploaded.FileLength,
+			MediaKey:      uploaded.MediaKey,
+			DirectPath:    &uploaded.DirectPath,
+		},
+	}
+	if mimeType == "application/pdf" {
+		if pages := pdfPageCount(documentData); pages > 0 {
+			msg.DocumentMessage.PageCount = proto.Uint32(pages)
+		}
+	}
+
+	return c.sendMessage(recipient, msg, opts)
+}
+
+// documentMimeType detects the MIME type of a document, falling back to the
+// file extension when the content isn't recognized
+func documentMimeType(data []byte, fileName string) string {
+	mimeType := detectMimeType(data)
+	if mimeType == "application/octet-stream" || mimeType == "text/plain" {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			mimeType, _, _ = mime.ParseMediaType(byExt)
+		}
+	}
+	return mimeType
+}
+
+// pdfPageCount counts the page objects in a PDF. PDFs that store their objects
+// in compressed object streams can't be counted this way and return 0.
+func pdfPageCount(data []byte) uint32 {
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return 0
+	}
+	return uint32(len(pdfPagePattern.FindAllIndex(data, -1)))
+}
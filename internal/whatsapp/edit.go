@@ -0,0 +1,110 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/w33ladalah/whrabbit/internal/storage"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrMessageNotFound is returned when a message wasn't sent through this client
+	ErrMessageNotFound = errors.New("message not found")
+	// ErrEditWindowExpired is returned when a message is too old to be edited
+	ErrEditWindowExpired = fmt.Errorf("messages can only be edited within %s of being sent", whatsmeow.EditWindow)
+	// ErrNotEditable is returned when editing a message that isn't text
+	ErrNotEditable = errors.New("only text messages can be edited")
+)
+
+// EditText replaces the text of a message sent through this client.
+// WhatsApp only accepts edits within whatsmeow.EditWindow of the original send.
+func (c *Client) EditText(messageID string, message string) (*SendResult, error) {
+	sent, err := c.findSent(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !sent.isText {
+		return nil, ErrNotEditable
+	}
+	if time.Since(sent.timestamp) > whatsmeow.EditWindow {
+		return nil, ErrEditWindowExpired
+	}
+
+	msg := c.Client.BuildEdit(sent.chat, messageID, &waProto.Message{
+		Conversation: proto.String(message),
+	})
+	return c.sendMessage(sent.chat, msg, SendOptions{})
+}
+
+// findSent returns a message sent by this session. Messages are looked up in the
+// message store when they aren't remembered, such as after a restart.
+func (c *Client) findSent(messageID string) (sentMessage, error) {
+	if sent, ok := c.sent.get(messageID); ok {
+		return sent, nil
+	}
+	if c.messages == nil {
+		return sentMessage{}, ErrMessageNotFound
+	}
+
+	stored, err := c.messages.GetMessage(context.Background(), c.sessionID, messageID)
+	if errors.Is(err, storage.ErrMessageNotFound) {
+		return sentMessage{}, ErrMessageNotFound
+	}
+	if err != nil {
+		return sentMessage{}, err
+	}
+	if !stored.FromMe {
+		return sentMessage{}, ErrMessageNotFound
+	}
+	chat, err := types.ParseJID(stored.Chat)
+	if err != nil {
+		return sentMessage{}, fmt.Errorf("error reading chat of message %s: %v", messageID, err)
+	}
+	return sentMessage{
+		chat:      chat,
+		timestamp: stored.Timestamp,
+		isText:    stored.Type == "text",
+	}, nil
+}
+
+// RevokeMessage deletes a message for everyone. The chat may be empty for messages
+// sent through this client. Group admins can revoke other people's messages
+// by giving the sender.
+func (c *Client) RevokeMessage(chat string, messageID string, sender string) (*SendResult, error) {
+	var chatJID types.JID
+	if chat != "" {
+		var err error
+		chatJID, err = ParseJID(chat)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat: %v", err)
+		}
+	} else {
+		// Any message this session sent can be revoked, whatever its type or age
+		sent, err := c.findSent(messageID)
+		if errors.Is(err, ErrMessageNotFound) {
+			return nil, fmt.Errorf("%w: chat is required for messages not sent through this server", ErrMessageNotFound)
+		}
+		if err != nil {
+			return nil, err
+		}
+		chatJID = sent.chat
+	}
+
+	senderJID := types.EmptyJID
+	if sender != "" {
+		var err error
+		senderJID, err = ParseJID(sender)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender: %v", err)
+		}
+	}
+
+	msg := c.Client.BuildRevoke(chatJID, senderJID, messageID)
+	return c.sendMessage(chatJID, msg, SendOptions{})
+}
@@ -0,0 +1,263 @@
+package whatsapp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Event types forwarded to event sinks
+const (
+	EventMessage    = "message"
+	EventReceipt    = "receipt"
+	EventPresence   = "presence"
+	EventTyping     = "typing"
+	EventGroup      = "group"
+	EventConnection = "connection"
+)
+
+// EventTypes lists every event type a sink can receive
+var EventTypes = []string{EventMessage, EventReceipt, EventPresence, EventTyping, EventGroup, EventConnection}
+
+// Event is a WhatsApp event in a stable JSON shape. Data holds one of the *EventData
+// types matching Type.
+type Event struct {
+	// ID is unique per event, receivers can use it to drop duplicates
+	ID string `json:"id" example:"9f2c4e1a7b3d5f60"`
+	// Session is the ID of the session that received the event
+	Session   string      `json:"session" example:"default"`
+	Type      string      `json:"type" example:"message"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Chat returns the chat an event belongs to: the chat of messages, receipts and typing,
+// the group of group changes and the contact of presence updates
+func (e Event) Chat() string {
+	switch data := e.Data.(type) {
+	case MessageEventData:
+		return data.Chat
+	case ReceiptEventData:
+		return data.Chat
+	case TypingEventData:
+		return data.Chat
+	case GroupEventData:
+		return data.Group
+	case PresenceEventData:
+		return data.From
+	default:
+		return ""
+	}
+}
+
+// MessageEventData describes an inbound message
+type MessageEventData struct {
+	ID       string    `json:"id"`
+	Chat     string    `json:"chat"`
+	Sender   string    `json:"sender"`
+	PushName string    `json:"push_name,omitempty"`
+	FromMe   bool      `json:"from_me"`
+	IsGroup  bool      `json:"is_group"`
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// ReceiptEventData describes a delivery, read or played receipt
+type ReceiptEventData struct {
+	MessageIDs []string `json:"message_ids"`
+	Chat       string   `json:"chat"`
+	Sender     string   `json:"sender"`
+	// Status is delivered, read, played or another WhatsApp receipt type
+	Status string `json:"status"`
+}
+
+// PresenceEventData describes a contact coming online or going offline
+type PresenceEventData struct {
+	From      string     `json:"from"`
+	Available bool       `json:"available"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"`
+}
+
+// TypingEventData describes a contact typing or recording in a chat
+type TypingEventData struct {
+	Chat   string `json:"chat"`
+	Sender string `json:"sender"`
+	// State is composing or paused
+	State string `json:"state"`
+	// Media is audio while recording a voice note, empty while typing
+	Media string `json:"media,omitempty"`
+}
+
+// GroupEventData describes a change to a group. Only the fields that changed are set.
+type GroupEventData struct {
+	Group    string   `json:"group"`
+	Sender   string   `json:"sender,omitempty"`
+	Name     *string  `json:"name,omitempty"`
+	Topic    *string  `json:"topic,omitempty"`
+	Announce *bool    `json:"announce,omitempty"`
+	Locked   *bool    `json:"locked,omitempty"`
+	Joined   []string `json:"joined,omitempty"`
+	Left     []string `json:"left,omitempty"`
+	Promoted []string `json:"promoted,omitempty"`
+	Demoted  []string `json:"demoted,omitempty"`
+}
+
+// ConnectionEventData describes a change in the connection to WhatsApp
+type ConnectionEventData struct {
+	// Status is connected, disconnected, logged_out, replaced, temporarily_banned or
+	// client_outdated
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// EventSink receives the events of a client. HandleEvent is called from the
+// whatsmeow event loop and must not block.
+type EventSink interface {
+	HandleEvent(evt Event)
+}
+
+// AddEventSink registers a sink that receives every event of the client
+func (c *Client) AddEventSink(sink EventSink) {
+	c.sinksMux.Lock()
+	defer c.sinksMux.Unlock()
+	c.sinks = append(c.sinks, sink)
+}
+
+// emitEvent converts a whatsmeow event and passes it to the event sinks and WebSocket
+// clients. Events without a typed equivalent are ignored.
+func (c *Client) emitEvent(rawEvt interface{}) {
+	eventType, data := convertEvent(rawEvt)
+	if eventType == "" {
+		return
+	}
+
+	evt := Event{
+		ID:        newEventID(),
+		Session:   c.sessionID,
+		Type:      eventType,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	}
+
+	// WebSocket clients already get connection changes as status frames
+	if c.wsManager != nil && evt.Type != EventConnection {
+		c.wsManager.BroadcastEvent(evt.Type, evt.Chat(), evt)
+	}
+
+	c.sinksMux.RLock()
+	defer c.sinksMux.RUnlock()
+	for _, sink := range c.sinks {
+		sink.HandleEvent(evt)
+	}
+}
+
+// convertEvent returns the event type and typed data of a whatsmeow event
+func convertEvent(rawEvt interface{}) (string, interface{}) {
+	switch v := rawEvt.(type) {
+	case *events.Message:
+		return EventMessage, MessageEventData{
+			ID:       v.Info.ID,
+			Chat:     v.Info.Chat.ToNonAD().String(),
+			Sender:   v.Info.Sender.ToNonAD().String(),
+			PushName: v.Info.PushName,
+			FromMe:   v.Info.IsFromMe,
+			IsGroup:  v.Info.IsGroup,
+			Type:     messageType(v.Message),
+			Text:     messageText(v.Message),
+			SentAt:   v.Info.Timestamp,
+		}
+	case *events.Receipt:
+		status := string(v.Type)
+		if v.Type == types.ReceiptTypeDelivered {
+			status = "delivered"
+		}
+		return EventReceipt, ReceiptEventData{
+			MessageIDs: v.MessageIDs,
+			Chat:       v.Chat.ToNonAD().String(),
+			Sender:     v.Sender.ToNonAD().String(),
+			Status:     status,
+		}
+	case *events.Presence:
+		data := PresenceEventData{
+			From:      v.From.ToNonAD().String(),
+			Available: !v.Unavailable,
+		}
+		if !v.LastSeen.IsZero() {
+			data.LastSeen = &v.LastSeen
+		}
+		return EventPresence, data
+	case *events.ChatPresence:
+		return EventTyping, TypingEventData{
+			Chat:   v.Chat.ToNonAD().String(),
+			Sender: v.Sender.ToNonAD().String(),
+			State:  string(v.State),
+			Media:  string(v.Media),
+		}
+	case *events.GroupInfo:
+		return EventGroup, groupEventData(v)
+	case *events.Connected:
+		return EventConnection, ConnectionEventData{Status: "connected"}
+	case *events.Disconnected:
+		return EventConnection, ConnectionEventData{Status: "disconnected"}
+	case *events.LoggedOut:
+		return EventConnection, ConnectionEventData{Status: "logged_out", Reason: v.Reason.String()}
+	case *events.StreamReplaced:
+		return EventConnection, ConnectionEventData{Status: "replaced"}
+	case *events.TemporaryBan:
+		return EventConnection, ConnectionEventData{Status: "temporarily_banned", Reason: v.String()}
+	case *events.ClientOutdated:
+		return EventConnection, ConnectionEventData{Status: "client_outdated"}
+	default:
+		return "", nil
+	}
+}
+
+// groupEventData converts a group info change
+func groupEventData(v *events.GroupInfo) GroupEventData {
+	data := GroupEventData{
+		Group:    v.JID.String(),
+		Joined:   jidStrings(v.Join),
+		Left:     jidStrings(v.Leave),
+		Promoted: jidStrings(v.Promote),
+		Demoted:  jidStrings(v.Demote),
+	}
+	if v.Sender != nil {
+		data.Sender = v.Sender.ToNonAD().String()
+	}
+	if v.Name != nil {
+		data.Name = &v.Name.Name
+	}
+	if v.Topic != nil {
+		data.Topic = &v.Topic.Topic
+	}
+	if v.Announce != nil {
+		data.Announce = &v.Announce.IsAnnounce
+	}
+	if v.Locked != nil {
+		data.Locked = &v.Locked.IsLocked
+	}
+	return data
+}
+
+// jidStrings converts JIDs to strings, returning nil for an empty list
+func jidStrings(jids []types.JID) []string {
+	if len(jids) == 0 {
+		return nil
+	}
+	strs := make([]string, len(jids))
+	for i, jid := range jids {
+		strs[i] = jid.ToNonAD().String()
+	}
+	return strs
+}
+
+// newEventID returns a random event ID
+func newEventID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,147 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/w33ladalah/whrabbit/internal/storage"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNoMessageStore is returned when querying messages without a message store
+var ErrNoMessageStore = errors.New("message store is not configured")
+
+// SetMessageStore sets the store that inbound and outbound messages are recorded in
+func (c *Client) SetMessageStore(messageStore storage.MessageStore) {
+	c.messages = messageStore
+}
+
+// GetMessages returns the stored messages of a chat, newest first.
+// The sender filter accepts a phone number or JID like the send methods do.
+func (c *Client) GetMessages(chat string, query storage.MessageQuery) (*storage.MessagePage, error) {
+	if c.messages == nil {
+		return nil, ErrNoMessageStore
+	}
+
+	chatJID, err := ParseJID(chat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chat: %v", err)
+	}
+	if query.Sender != "" {
+		sender, err := ParseJID(query.Sender)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender: %v", err)
+		}
+		query.Sender = sender.ToNonAD().String()
+	}
+
+	return c.messages.GetMessages(context.Background(), c.sessionID, chatJID.ToNonAD().String(), query)
+}
+
+// storeMessage records a message in the message store, if one is set.
+// Failures are logged rather than returned so they never block sending or receiving.
+func (c *Client) storeMessage(id types.MessageID, chat, sender types.JID, fromMe bool, timestamp time.Time, msg *waProto.Message) {
+	if c.messages == nil || msg == nil {
+		return
+	}
+
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		log.Printf("Error encoding message %s for storage: %v", id, err)
+	}
+
+	err = c.messages.SaveMessage(context.Background(), &storage.Message{
+		ID:        id,
+		Session:   c.sessionID,
+		Chat:      chat.ToNonAD().String(),
+		Sender:    sender.ToNonAD().String(),
+		FromMe:    fromMe,
+		Type:      messageType(msg),
+		Text:      messageText(msg),
+		Timestamp: timestamp,
+		Raw:       raw,
+	})
+	if err != nil {
+		log.Printf("Error storing message %s: %v", id, err)
+	}
+}
+
+// messageType returns a short name for the kind of content a message carries
+func messageType(msg *waProto.Message) string {
+	msg = unwrapEdit(msg)
+	switch {
+	case msg.Conversation != nil, msg.ExtendedTextMessage != nil:
+		return "text"
+	case msg.ImageMessage != nil:
+		return "image"
+	case msg.VideoMessage != nil:
+		return "video"
+	case msg.AudioMessage != nil:
+		return "audio"
+	case msg.DocumentMessage != nil:
+		return "document"
+	case msg.StickerMessage != nil:
+		return "sticker"
+	case msg.LocationMessage != nil:
+		return "location"
+	case msg.LiveLocationMessage != nil:
+		return "live_location"
+	case msg.ContactMessage != nil, msg.ContactsArrayMessage != nil:
+		return "contact"
+	case pollCreationMessage(msg) != nil:
+		return "poll"
+	case msg.PollUpdateMessage != nil:
+		return "poll_vote"
+	case msg.ReactionMessage != nil:
+		return "reaction"
+	case msg.ProtocolMessage != nil:
+		switch msg.ProtocolMessage.GetType() {
+		case waProto.ProtocolMessage_REVOKE:
+			return "revoke"
+		case waProto.ProtocolMessage_MESSAGE_EDIT:
+			return "edit"
+		}
+		return "protocol"
+	default:
+		return "unknown"
+	}
+}
+
+// messageText returns the text or caption of a message, or an empty string
+func messageText(msg *waProto.Message) string {
+	msg = unwrapEdit(msg)
+	switch {
+	case msg.Conversation != nil:
+		return msg.GetConversation()
+	case msg.ExtendedTextMessage != nil:
+		return msg.ExtendedTextMessage.GetText()
+	case msg.ImageMessage != nil:
+		return msg.ImageMessage.GetCaption()
+	case msg.VideoMessage != nil:
+		return msg.VideoMessage.GetCaption()
+	case msg.DocumentMessage != nil:
+		return msg.DocumentMessage.GetCaption()
+	case msg.ReactionMessage != nil:
+		return msg.ReactionMessage.GetText()
+	case pollCreationMessage(msg) != nil:
+		return pollCreationMessage(msg).GetName()
+	case msg.ProtocolMessage != nil && msg.ProtocolMessage.EditedMessage != nil:
+		return messageText(msg.ProtocolMessage.EditedMessage)
+	default:
+		return ""
+	}
+}
+
+// unwrapEdit returns the protocol message inside an edit, which is sent wrapped in
+// EditedMessage, or msg itself
+func unwrapEdit(msg *waProto.Message) *waProto.Message {
+	if inner := msg.GetEditedMessage().GetMessage(); inner != nil {
+		return inner
+	}
+	return msg
+}
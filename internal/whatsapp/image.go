@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // GIF decoder
+	"image/jpeg"
+	_ "image/png" // PNG decoder
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // WebP decoder
+)
+
+// thumbnailQuality is the JPEG quality used for generated thumbnails
+const thumbnailQuality = 75
+
+// decodeImage decodes a JPEG, PNG, GIF or WebP image
+func decodeImage(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %v", err)
+	}
+	return img, nil
+}
+
+// imageThumbnail scales an image down to thumbnailWidth pixels wide and encodes it as JPEG
+func imageThumbnail(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, fmt.Errorf("image has no pixels")
+	}
+
+	width := thumbnailWidth
+	if bounds.Dx() < width {
+		width = bounds.Dx()
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height == 0 {
+		height = 1
+	}
+
+	thumb := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(thumb, thumb.Bounds(), img, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %v", err)
+	}
+	return buf.Bytes(), nil
+}
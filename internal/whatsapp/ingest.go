@@ -0,0 +1,173 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/w33ladalah/whrabbit/internal/config"
+)
+
+// MediaSource points to media given by URL or as base64 data instead of a multipart upload
+type MediaSource struct {
+	// URL is fetched by the server, only http and https are allowed
+	URL string `json:"url,omitempty" example:"https://example.com/photo.jpg"`
+	// Data is the base64 encoded media, optionally as a data URI
+	Data string `json:"data,omitempty" example:"iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="`
+}
+
+// Media is media loaded from a MediaSource
+type Media struct {
+	Data []byte
+	// FileName is taken from the URL path, empty for base64 data
+	FileName string
+}
+
+// Reader returns a reader over the media data
+func (m *Media) Reader() io.Reader {
+	return bytes.NewReader(m.Data)
+}
+
+// LoadMedia loads media from a URL or base64 data, enforcing the configured size limit.
+// For URLs the response Content-Type must start with kind + "/" unless kind is empty
+// or the server doesn't report a specific type.
+func LoadMedia(source MediaSource, kind string) (*Media, error) {
+	switch {
+	case source.URL != "" && source.Data != "":
+		return nil, fmt.Errorf("only one of url or data can be given")
+	case source.URL != "":
+		return fetchMedia(source.URL, kind)
+	case source.Data != "":
+		return decodeMedia(source.Data)
+	default:
+		return nil, fmt.Errorf("either url or data is required")
+	}
+}
+
+// fetchMedia downloads media from an http or https URL
+func fetchMedia(rawURL string, kind string) (*Media, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid media URL: %s", rawURL)
+	}
+
+	httpClient := publicHTTPClient(config.GetMediaFetchTimeout())
+	resp, err := httpClient.Get(parsed.String())
+	if err != nil {
+		return nil, fmt.Errorf("error fetching media: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching media: unexpected status %s", resp.Status)
+	}
+
+	if kind != "" {
+		contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if !isGenericContentType(contentType) && !strings.HasPrefix(contentType, kind+"/") &&
+			!(kind == "audio" && contentType == "application/ogg") {
+			return nil, fmt.Errorf("unexpected content type for %s: %s", kind, contentType)
+		}
+	}
+
+	maxSize := config.GetMediaMaxSize()
+	if resp.ContentLength > maxSize {
+		return nil, fmt.Errorf("media exceeds the maximum size of %d bytes", maxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading media: %v", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("media exceeds the maximum size of %d bytes", maxSize)
+	}
+
+	fileName := path.Base(parsed.Path)
+	if fileName == "/" || fileName == "." {
+		fileName = ""
+	}
+
+	return &Media{Data: data, FileName: fileName}, nil
+}
+
+// publicHTTPClient returns an HTTP client for URLs given by API callers or found in
+// messages. It only connects to public addresses, so those URLs can't reach the server
+// itself or its private network, also not through redirects or DNS names that resolve
+// to private addresses.
+func publicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: denyPrivateAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would be the address checked instead of the target
+	transport.Proxy = nil
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// denyPrivateAddress is a net.Dialer Control hook that refuses to connect to loopback,
+// private, link-local and other non-public addresses
+func denyPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %s: %v", address, err)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("invalid address %s: %v", address, err)
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("address %s is not public", ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, which netip doesn't count as private
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// decodeMedia decodes base64 media, stripping a data URI prefix if present
+func decodeMedia(data string) (*Media, error) {
+	if strings.HasPrefix(data, "data:") {
+		_, encoded, found := strings.Cut(data, ",")
+		if !found {
+			return nil, fmt.Errorf("invalid data URI")
+		}
+		data = encoded
+	}
+
+	maxSize := config.GetMediaMaxSize()
+	if int64(base64.StdEncoding.DecodedLen(len(data))) > maxSize+2 {
+		return nil, fmt.Errorf("media exceeds the maximum size of %d bytes", maxSize)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 data: %v", err)
+	}
+	if int64(len(decoded)) > maxSize {
+		return nil, fmt.Errorf("media exceeds the maximum size of %d bytes", maxSize)
+	}
+
+	return &Media{Data: decoded}, nil
+}
+
+// isGenericContentType reports whether a content type says nothing about the media kind
+func isGenericContentType(contentType string) bool {
+	return contentType == "" || contentType == "application/octet-stream" || contentType == "binary/octet-stream"
+}
@@ -0,0 +1,100 @@
+package whatsapp
+
+import (
+	"fmt"
+	"time"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Location is a pinned location such as a pickup point
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	Name      string
+	Address   string
+	URL       string
+}
+
+// LiveLocation is a single update of a shared live location
+type LiveLocation struct {
+	Latitude         float64
+	Longitude        float64
+	AccuracyInMeters uint32
+	SpeedInMps       float32
+	// Heading is in degrees clockwise from magnetic north
+	Heading uint32
+	Caption string
+	// SequenceNumber must increase with every update, defaults to the current time
+	SequenceNumber int64
+}
+
+// SendLocation sends a location message to a WhatsApp number
+func (c *Client) SendLocation(to string, location Location, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	if err := validateCoordinates(location.Latitude, location.Longitude); err != nil {
+		return nil, err
+	}
+
+	msg := &waProto.Message{
+		LocationMessage: &waProto.LocationMessage{
+			DegreesLatitude:  proto.Float64(location.Latitude),
+			DegreesLongitude: proto.Float64(location.Longitude),
+			Name:             proto.String(location.Name),
+			Address:          proto.String(location.Address),
+			URL:              proto.String(location.URL),
+		},
+	}
+
+	return c.sendMessage(recipient, msg, opts)
+}
+
+// SendLiveLocation sends a live location update to a WhatsApp number
+func (c *Client) SendLiveLocation(to string, location LiveLocation, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	if err := validateCoordinates(location.Latitude, location.Longitude); err != nil {
+		return nil, err
+	}
+	if location.Heading >= 360 {
+		return nil, fmt.Errorf("heading must be between 0 and 359 degrees")
+	}
+
+	sequenceNumber := location.SequenceNumber
+	if sequenceNumber == 0 {
+		sequenceNumber = time.Now().UnixMilli()
+	}
+
+	msg := &waProto.Message{
+		LiveLocationMessage: &waProto.LiveLocationMessage{
+			DegreesLatitude:                   proto.Float64(location.Latitude),
+			DegreesLongitude:                  proto.Float64(location.Longitude),
+			AccuracyInMeters:                  proto.Uint32(location.AccuracyInMeters),
+			SpeedInMps:                        proto.Float32(location.SpeedInMps),
+			DegreesClockwiseFromMagneticNorth: proto.Uint32(location.Heading),
+			Caption:                           proto.String(location.Caption),
+			SequenceNumber:                    proto.Int64(sequenceNumber),
+		},
+	}
+
+	return c.sendMessage(recipient, msg, opts)
+}
+
+// validateCoordinates checks that latitude and longitude are within range
+func validateCoordinates(latitude, longitude float64) error {
+	if latitude < -90 || latitude > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if longitude < -180 || longitude > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/w33ladalah/whrabbit/internal/api/websocket"
+	"go.mau.fi/whatsmeow"
+)
+
+// pairTimeout is how long to wait for a new login connection before pairing
+const pairTimeout = 15 * time.Second
+
+// pairClientName is the linked device name shown on the phone. WhatsApp only accepts
+// common browsers and operating systems in the "Browser (OS)" format.
+const pairClientName = "Chrome (Linux)"
+
+// Login errors
+var (
+	// ErrAlreadyLoggedIn is returned when pairing a session that is already linked to an account
+	ErrAlreadyLoggedIn = errors.New("session is already logged in")
+	// ErrNotLoggedIn is returned when logging out a session that isn't linked to an account
+	ErrNotLoggedIn = errors.New("session is not logged in")
+	// ErrNoQRCode is returned when WhatsApp hasn't sent a QR code yet
+	ErrNoQRCode = errors.New("no QR code available yet")
+)
+
+// SessionStatus describes the login state of a session
+type SessionStatus struct {
+	Session   string `json:"session" example:"default"`
+	Connected bool   `json:"connected"`
+	LoggedIn  bool   `json:"logged_in"`
+	// JID is the linked WhatsApp account, empty until the session is paired
+	JID      string `json:"jid,omitempty" example:"6281234567890:12@s.whatsapp.net"`
+	PushName string `json:"push_name,omitempty" example:"Jane"`
+	// Platform is the platform of the phone the session is linked to
+	Platform string `json:"platform,omitempty" example:"android"`
+	// LastConnected is when the session last connected to WhatsApp
+	LastConnected *time.Time `json:"last_connected,omitempty"`
+}
+
+// Status returns the login state of the session
+func (c *Client) Status() SessionStatus {
+	status := SessionStatus{
+		Session:   c.sessionID,
+		Connected: c.IsConnected(),
+		LoggedIn:  c.IsLoggedIn(),
+		PushName:  c.Store.PushName,
+		Platform:  c.Store.Platform,
+	}
+	if c.Store.ID != nil {
+		status.JID = c.Store.ID.String()
+	}
+
+	c.connectedAtMux.RLock()
+	if !c.connectedAt.IsZero() {
+		connectedAt := c.connectedAt
+		status.LastConnected = &connectedAt
+	}
+	c.connectedAtMux.RUnlock()
+
+	return status
+}
+
+// LatestQR returns the QR code to scan to log the session in. It waits for the first
+// code of a login that is starting, and starts a new login when the last one has ended.
+func (c *Client) LatestQR() (string, error) {
+	if c.Store.ID != nil {
+		return "", ErrAlreadyLoggedIn
+	}
+	if code := c.wsManager.LatestQR(); code != "" {
+		return code, nil
+	}
+	return c.startLogin()
+}
+
+// Logout unlinks the session from its WhatsApp account, deletes the device from the store
+// and starts a new login so the session can be linked again
+func (c *Client) Logout() error {
+	if c.Store.ID == nil {
+		return ErrNotLoggedIn
+	}
+
+	c.stopReconnect()
+	if err := c.Client.Logout(); err != nil {
+		// WhatsApp couldn't be told, still forget the device locally
+		log.Printf("Error logging out of WhatsApp: %v", err)
+		c.Client.Disconnect()
+		if c.Store.ID != nil {
+			if err := c.Store.Delete(); err != nil {
+				return fmt.Errorf("error deleting device: %v", err)
+			}
+		}
+	}
+	c.wsManager.BroadcastConnectionStatus(websocket.StateLoggedOut, "WhatsApp logged out")
+
+	c.connectInBackground()
+	return nil
+}
+
+// PairPhone starts linking the session to the WhatsApp account of a phone number and
+// returns the 8-character code to enter on the phone under Linked devices. The code is
+// also sent to WebSocket clients as a pair_code frame.
+func (c *Client) PairPhone(phone string) (string, error) {
+	if c.Store.ID != nil {
+		return "", ErrAlreadyLoggedIn
+	}
+
+	// Pairing needs the connection of a pending login, start a new one if the last
+	// login has ended
+	if _, err := c.startLogin(); err != nil {
+		return "", err
+	}
+
+	code, err := c.Client.PairPhone(phone, true, whatsmeow.PairClientChrome, pairClientName)
+	if err != nil {
+		return "", fmt.Errorf("error requesting pair code: %v", err)
+	}
+
+	if c.wsManager != nil {
+		c.wsManager.BroadcastPairCode(code)
+	}
+	return code, nil
+}
+
+// qrLogin is a QR login in progress
+type qrLogin struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// ready is closed when the first QR code arrives and done when the login has ended
+	ready     chan struct{}
+	readyOnce sync.Once
+	done      chan struct{}
+	// code is the latest QR code, err is why the login failed
+	code    string
+	err     error
+	codeMux sync.Mutex
+}
+
+// setCode records the latest QR code of the login
+func (l *qrLogin) setCode(code string) {
+	l.codeMux.Lock()
+	l.code = code
+	l.codeMux.Unlock()
+	l.readyOnce.Do(func() { close(l.ready) })
+}
+
+// latestCode returns the latest QR code of the login
+func (l *qrLogin) latestCode() string {
+	l.codeMux.Lock()
+	defer l.codeMux.Unlock()
+	return l.code
+}
+
+// beginLogin returns the QR login in progress, or registers a new one that the caller
+// has to run when started is true
+func (c *Client) beginLogin(ctx context.Context) (login *qrLogin, started bool) {
+	c.loginMux.Lock()
+	defer c.loginMux.Unlock()
+	if c.login != nil {
+		return c.login, false
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.login = &qrLogin{
+		ctx:    ctx,
+		cancel: cancel,
+		ready:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	return c.login, true
+}
+
+// runLogin runs a QR login until it succeeds, fails or times out. A login whose
+// connection dropped is started again once it has ended.
+func (c *Client) runLogin(login *qrLogin) error {
+	restart, err := c.loginWithQR(login)
+
+	c.loginMux.Lock()
+	if c.login == login {
+		c.login = nil
+	}
+	c.loginMux.Unlock()
+	login.err = err
+	login.cancel()
+	close(login.done)
+
+	if restart {
+		c.connectInBackground()
+	}
+	return err
+}
+
+// loginWithQR connects and sends the QR codes of a login to WebSocket clients. It reports
+// whether the login ended because its connection dropped.
+func (c *Client) loginWithQR(login *qrLogin) (restart bool, err error) {
+	qrChan, err := c.GetQRChannel(login.ctx)
+	if err != nil {
+		return false, fmt.Errorf("error starting QR login: %v", err)
+	}
+	c.loginDropped.Store(false)
+	if err := c.Client.Connect(); err != nil {
+		return false, fmt.Errorf("error connecting to WhatsApp: %v", err)
+	}
+
+	for {
+		var evt whatsmeow.QRChannelItem
+		var ok bool
+		select {
+		case <-login.ctx.Done():
+			// The login was ended on purpose
+			return false, nil
+		case evt, ok = <-qrChan:
+		}
+		if !ok {
+			return restart, nil
+		}
+
+		if evt.Event == "code" {
+			// Broadcast QR code to all connected WebSocket clients
+			login.setCode(evt.Code)
+			c.wsManager.BroadcastQR(evt.Code)
+			fmt.Println("QR code:", evt.Code)
+			continue
+		}
+		fmt.Println("Login event:", evt.Event)
+		switch evt.Event {
+		case "success":
+			c.wsManager.BroadcastConnectionStatus(websocket.StateConnected, "WhatsApp connected successfully!")
+		case "timeout":
+			if c.loginDropped.Swap(false) {
+				// The connection dropped before the codes ran out, the new login
+				// sends new ones
+				c.wsManager.BroadcastConnectionStatus(websocket.StateDisconnected, "WhatsApp disconnected, starting a new login")
+				restart = true
+				break
+			}
+			// whatsmeow has disconnected, the next QR code request starts a new login
+			c.wsManager.BroadcastConnectionStatus(websocket.StateQRTimeout, "QR code expired, request /session/qr to get a new one")
+		}
+	}
+}
+
+// endLogin stops the QR login in progress, if any
+func (c *Client) endLogin() {
+	c.loginMux.Lock()
+	defer c.loginMux.Unlock()
+	if c.login != nil {
+		c.login.cancel()
+	}
+}
+
+// startLogin waits until the login in progress is ready to pair, which WhatsApp signals
+// with the first QR code, starting a login when none is running. The latest code is
+// returned.
+func (c *Client) startLogin() (string, error) {
+	login, started := c.beginLogin(context.Background())
+	if started {
+		go func() {
+			if err := c.runLogin(login); err != nil {
+				log.Printf("Error logging in session %s: %v", c.sessionID, err)
+			}
+		}()
+	}
+
+	select {
+	case <-login.ready:
+	case <-login.done:
+	case <-time.After(pairTimeout):
+		return "", fmt.Errorf("timed out connecting to WhatsApp")
+	}
+
+	select {
+	case <-login.done:
+		// The codes of a login that has ended can't be scanned anymore
+		if login.err != nil {
+			return "", login.err
+		}
+		return "", ErrNoQRCode
+	default:
+		return login.latestCode(), nil
+	}
+}
@@ -0,0 +1,63 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	"go.mau.fi/whatsmeow"
+)
+
+// thumbnailWidth is the width in pixels of generated JPEG thumbnails
+const thumbnailWidth = 72
+
+// ffmpegTimeout limits how long a single ffmpeg invocation may run
+const ffmpegTimeout = 15 * time.Second
+
+// detectMimeType returns the MIME type of the given data without parameters
+func detectMimeType(data []byte) string {
+	mimeType, _, _ := strings.Cut(mimetype.Detect(data).String(), ";")
+	return mimeType
+}
+
+// uploadMedia uploads the given data to WhatsApp as the given media type
+func (c *Client) uploadMedia(data []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	return c.Client.Upload(context.Background(), data, mediaType)
+}
+
+// runFFmpeg writes data to a temporary file and runs ffmpeg on it, returning stdout.
+// The input file path is appended after "-i" and args follow it.
+// Returns an error if ffmpeg is not installed.
+func runFFmpeg(data []byte, args ...string) ([]byte, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %v", err)
+	}
+
+	// ffmpeg needs a seekable input for most containers, so use a temp file instead of stdin
+	tmp, err := os.CreateTemp("", "whrabbit-media-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("error writing temp file: %v", err)
+	}
+	tmp.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), ffmpegTimeout)
+	defer cancel()
+
+	cmdArgs := append([]string{"-hide_banner", "-loglevel", "error", "-i", tmp.Name()}, args...)
+	out, err := exec.CommandContext(ctx, ffmpeg, cmdArgs...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("error running ffmpeg: %v", err)
+	}
+	return out, nil
+}
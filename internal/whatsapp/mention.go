@@ -0,0 +1,56 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// parseMentions parses the mentioned phone numbers or JIDs and checks that each
+// one is tagged as @number in the message. Duplicates are dropped.
+func parseMentions(message string, mentions []string) ([]string, error) {
+	jids := make([]string, 0, len(mentions))
+	seen := make(map[string]bool, len(mentions))
+	for _, mention := range mentions {
+		jid, err := ParseJID(strings.TrimSpace(mention))
+		if err != nil {
+			return nil, fmt.Errorf("invalid mention %q: %v", mention, err)
+		}
+		if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+			return nil, fmt.Errorf("invalid mention %q: only users can be mentioned", mention)
+		}
+		if !containsMention(message, jid.User) {
+			return nil, fmt.Errorf("mention @%s does not appear in the message", jid.User)
+		}
+
+		jidStr := jid.ToNonAD().String()
+		if !seen[jidStr] {
+			seen[jidStr] = true
+			jids = append(jids, jidStr)
+		}
+	}
+	return jids, nil
+}
+
+// containsMention reports whether the message tags user as @number, where the number
+// isn't just the start of a longer one
+func containsMention(message, user string) bool {
+	tag := "@" + user
+	for rest := message; ; {
+		i := strings.Index(rest, tag)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(tag):]
+		if rest == "" || rest[0] < '0' || rest[0] > '9' {
+			return true
+		}
+	}
+}
+
+// ValidateMentions checks that every mention is a valid user tagged as @number in the message
+func ValidateMentions(message string, mentions []string) error {
+	_, err := parseMentions(message, mentions)
+	return err
+}
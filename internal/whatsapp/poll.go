@@ -0,0 +1,238 @@
+package whatsapp
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// ErrPollNotFound is returned when results are requested for an unknown poll
+var ErrPollNotFound = errors.New("poll not found")
+
+// maxPollOptions is the most options WhatsApp allows in a poll
+const maxPollOptions = 12
+
+// PollResults is the current tally of a poll
+type PollResults struct {
+	ID              string             `json:"id" example:"3EB0C431C26A1916E5F6"`
+	Chat            string             `json:"chat" example:"1234567890@s.whatsapp.net"`
+	Question        string             `json:"question" example:"Which day works best?"`
+	SelectableCount int                `json:"selectable_count" example:"1"`
+	Options         []PollOptionResult `json:"options"`
+	TotalVoters     int                `json:"total_voters" example:"3"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+// PollOptionResult is the tally of a single poll option
+type PollOptionResult struct {
+	Name   string   `json:"name" example:"Monday"`
+	Votes  int      `json:"votes" example:"2"`
+	Voters []string `json:"voters"`
+}
+
+// poll is a tracked poll and the latest vote of each voter
+type poll struct {
+	chat            types.JID
+	question        string
+	options         []string
+	selectableCount int
+	createdAt       time.Time
+	// optionHashes maps the SHA-256 hash of an option name to the name
+	optionHashes map[[32]byte]string
+	// votes maps a voter JID to the options they selected
+	votes map[string][]string
+}
+
+// pollStore keeps the polls seen by the client in memory
+type pollStore struct {
+	polls map[types.MessageID]*poll
+	mux   sync.RWMutex
+}
+
+func newPollStore() *pollStore {
+	return &pollStore{
+		polls: make(map[types.MessageID]*poll),
+	}
+}
+
+// SendPoll sends a poll to a WhatsApp number or group.
+// A selectableCount of 0 allows voters to pick any number of options.
+func (c *Client) SendPoll(to string, question string, options []string, selectableCount int, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	if strings.TrimSpace(question) == "" {
+		return nil, fmt.Errorf("poll question is required")
+	}
+	if len(options) < 2 || len(options) > maxPollOptions {
+		return nil, fmt.Errorf("poll must have between 2 and %d options", maxPollOptions)
+	}
+	seen := make(map[string]bool, len(options))
+	for _, option := range options {
+		if strings.TrimSpace(option) == "" {
+			return nil, fmt.Errorf("poll options can't be empty")
+		}
+		if seen[option] {
+			return nil, fmt.Errorf("duplicate poll option: %s", option)
+		}
+		seen[option] = true
+	}
+	if selectableCount < 0 || selectableCount > len(options) {
+		return nil, fmt.Errorf("selectable count must be between 0 and %d", len(options))
+	}
+
+	msg := c.Client.BuildPollCreation(question, options, selectableCount)
+	resp, err := c.sendMessage(recipient, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.polls.add(resp.ID, recipient, question, options, selectableCount, resp.Timestamp)
+	return resp, nil
+}
+
+// GetPollResults returns the current tally of a poll
+func (c *Client) GetPollResults(id string) (*PollResults, error) {
+	return c.polls.results(id)
+}
+
+// handlePollMessage tracks poll creations and applies poll votes from an incoming message
+func (c *Client) handlePollMessage(evt *events.Message) {
+	if creation := pollCreationMessage(evt.Message); creation != nil {
+		options := make([]string, len(creation.GetOptions()))
+		for i, option := range creation.GetOptions() {
+			options[i] = option.GetOptionName()
+		}
+		c.polls.add(evt.Info.ID, evt.Info.Chat, creation.GetName(), options, int(creation.GetSelectableOptionsCount()), evt.Info.Timestamp)
+		return
+	}
+
+	update := evt.Message.GetPollUpdateMessage()
+	if update == nil {
+		return
+	}
+
+	vote, err := c.Client.DecryptPollVote(evt)
+	if err != nil {
+		log.Printf("Error decrypting poll vote from %s: %v", evt.Info.Sender, err)
+		return
+	}
+
+	pollID := update.GetPollCreationMessageKey().GetID()
+	if !c.polls.vote(pollID, evt.Info.Sender.ToNonAD().String(), vote.GetSelectedOptions()) {
+		log.Printf("Received vote for unknown poll %s", pollID)
+	}
+}
+
+// pollCreationMessage returns the poll creation message of any version, or nil
+func pollCreationMessage(msg *waProto.Message) *waProto.PollCreationMessage {
+	switch {
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2()
+	case msg.GetPollCreationMessageV3() != nil:
+		return msg.GetPollCreationMessageV3()
+	}
+	return nil
+}
+
+func (s *pollStore) add(id types.MessageID, chat types.JID, question string, options []string, selectableCount int, createdAt time.Time) {
+	hashes := make(map[[32]byte]string, len(options))
+	for _, option := range options {
+		hashes[sha256.Sum256([]byte(option))] = option
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, exists := s.polls[id]; exists {
+		return
+	}
+	s.polls[id] = &poll{
+		chat:            chat,
+		question:        question,
+		options:         options,
+		selectableCount: selectableCount,
+		createdAt:       createdAt,
+		optionHashes:    hashes,
+		votes:           make(map[string][]string),
+	}
+}
+
+// vote replaces the voter's previous selection, returns false if the poll is unknown
+func (s *pollStore) vote(id types.MessageID, voter string, selectedHashes [][]byte) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	p, ok := s.polls[id]
+	if !ok {
+		return false
+	}
+
+	// An empty selection means the voter retracted their vote
+	if len(selectedHashes) == 0 {
+		delete(p.votes, voter)
+		return true
+	}
+
+	selected := make([]string, 0, len(selectedHashes))
+	for _, hash := range selectedHashes {
+		if len(hash) != sha256.Size {
+			continue
+		}
+		if option, ok := p.optionHashes[[32]byte(hash)]; ok {
+			selected = append(selected, option)
+		}
+	}
+	p.votes[voter] = selected
+	return true
+}
+
+func (s *pollStore) results(id string) (*PollResults, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	p, ok := s.polls[id]
+	if !ok {
+		return nil, ErrPollNotFound
+	}
+
+	results := &PollResults{
+		ID:              id,
+		Chat:            p.chat.String(),
+		Question:        p.question,
+		SelectableCount: p.selectableCount,
+		Options:         make([]PollOptionResult, len(p.options)),
+		TotalVoters:     len(p.votes),
+		CreatedAt:       p.createdAt,
+	}
+
+	index := make(map[string]int, len(p.options))
+	for i, option := range p.options {
+		index[option] = i
+		results.Options[i] = PollOptionResult{Name: option, Voters: []string{}}
+	}
+	for voter, selected := range p.votes {
+		for _, option := range selected {
+			result := &results.Options[index[option]]
+			result.Votes++
+			result.Voters = append(result.Voters, voter)
+		}
+	}
+	for i := range results.Options {
+		sort.Strings(results.Options[i].Voters)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,234 @@
+package whatsapp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/w33ladalah/whrabbit/internal/config"
+	"golang.org/x/net/html"
+)
+
+// maxPreviewRedirects limits how many redirects are followed when fetching a preview
+const maxPreviewRedirects = 5
+
+// urlPattern matches http and https URLs in message text
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// linkPreview is the preview card shown for a URL in a text message
+type linkPreview struct {
+	// MatchedText is the URL as it appears in the message
+	MatchedText string
+	Title       string
+	Description string
+	ImageURL    string
+	Thumbnail   []byte
+}
+
+// findURL returns the first http or https URL in text, or an empty string
+func findURL(text string) string {
+	match := urlPattern.FindString(text)
+	// Trailing punctuation usually belongs to the sentence, not the URL
+	return strings.TrimRight(match, ".,;:!?)]}'")
+}
+
+// fetchLinkPreview reads the OpenGraph metadata of a page and downloads its preview image.
+// A missing or broken image doesn't fail the preview.
+func fetchLinkPreview(rawURL string) (*linkPreview, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %v", err)
+	}
+
+	body, err := fetchPreviewResource(pageURL, "text/html")
+	if err != nil {
+		return nil, err
+	}
+
+	preview := parseLinkPreview(body)
+	if preview.Title == "" {
+		return nil, fmt.Errorf("page has no title")
+	}
+	preview.MatchedText = rawURL
+
+	if preview.ImageURL != "" {
+		thumbnail, err := fetchPreviewThumbnail(pageURL, preview.ImageURL)
+		if err != nil {
+			// The preview is still useful without an image
+			log.Printf("Could not fetch link preview image %s: %v", preview.ImageURL, err)
+		}
+		preview.Thumbnail = thumbnail
+	}
+
+	return preview, nil
+}
+
+// fetchPreviewThumbnail downloads a preview image and scales it down to a JPEG thumbnail
+func fetchPreviewThumbnail(pageURL *url.URL, imageURL string) ([]byte, error) {
+	ref, err := url.Parse(imageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image URL: %v", err)
+	}
+
+	data, err := fetchPreviewResource(pageURL.ResolveReference(ref), "image")
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := decodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	return imageThumbnail(img)
+}
+
+// fetchPreviewResource downloads a page or image for a link preview from a public
+// address, enforcing the configured host lists, timeout and size limit. The response Content-Type must
+// start with contentType.
+func fetchPreviewResource(target *url.URL, contentType string) ([]byte, error) {
+	if err := checkPreviewURL(target); err != nil {
+		return nil, err
+	}
+
+	httpClient := publicHTTPClient(config.GetLinkPreviewTimeout())
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxPreviewRedirects {
+			return errors.New("too many redirects")
+		}
+		return checkPreviewURL(req.URL)
+	}
+	resp, err := httpClient.Get(target.String())
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %v", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching %s: unexpected status %s", target, resp.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, contentType) {
+		return nil, fmt.Errorf("unexpected content type for %s: %s", target, mediaType)
+	}
+
+	maxSize := config.GetLinkPreviewMaxSize()
+	if resp.ContentLength > maxSize {
+		return nil, fmt.Errorf("%s exceeds the maximum size of %d bytes", target, maxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", target, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%s exceeds the maximum size of %d bytes", target, maxSize)
+	}
+	return data, nil
+}
+
+// checkPreviewURL checks that a URL is http or https and that its host passes the
+// configured allow and deny lists
+func checkPreviewURL(target *url.URL) error {
+	if (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("invalid URL: %s", target)
+	}
+
+	host := strings.ToLower(target.Hostname())
+	if matchesHost(host, config.GetLinkPreviewDeniedHosts()) {
+		return fmt.Errorf("host %s is not allowed for link previews", host)
+	}
+	if allowed := config.GetLinkPreviewAllowedHosts(); len(allowed) > 0 && !matchesHost(host, allowed) {
+		return fmt.Errorf("host %s is not allowed for link previews", host)
+	}
+	return nil
+}
+
+// matchesHost reports whether host is one of the listed hosts or a subdomain of one
+func matchesHost(host string, hosts []string) bool {
+	for _, entry := range hosts {
+		entry = strings.ToLower(strings.TrimPrefix(entry, "."))
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLinkPreview reads the OpenGraph title, description and image of an HTML page,
+// falling back to the <title> element and description meta tag
+func parseLinkPreview(page []byte) *linkPreview {
+	preview := &linkPreview{}
+	var title, description string
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(page))
+	inTitle := false
+tokens:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			break tokens
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = true
+			case "meta":
+				property, content := metaAttributes(token)
+				switch property {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image", "og:image:url", "og:image:secure_url":
+					if preview.ImageURL == "" {
+						preview.ImageURL = content
+					}
+				case "description":
+					description = content
+				}
+			case "body":
+				// Metadata lives in the head, there's no need to read the rest of the page
+				break tokens
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "title" {
+				inTitle = false
+			}
+		}
+	}
+
+	if preview.Title == "" {
+		preview.Title = strings.TrimSpace(title)
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+	return preview
+}
+
+// metaAttributes returns the property (or name) and content of a meta tag
+func metaAttributes(token html.Token) (property string, content string) {
+	for _, attr := range token.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name":
+			if property == "" {
+				property = strings.ToLower(attr.Val)
+			}
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+	return property, content
+}
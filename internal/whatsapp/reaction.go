@@ -0,0 +1,58 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// MessageRef identifies an earlier message in a chat
+type MessageRef struct {
+	// Chat is the phone number or JID of the chat containing the message
+	Chat string `json:"chat" binding:"required" example:"1234567890"`
+	// MessageID is the ID of the message
+	MessageID string `json:"message_id" binding:"required" example:"3EB0C431C26A1916E5F6"`
+	// Sender is the phone number or JID of the message's author.
+	// Required for other people's messages in groups, defaults to the chat in private chats.
+	Sender string `json:"sender,omitempty" example:"1234567890"`
+	// FromMe marks the message as sent by this account
+	FromMe bool `json:"from_me,omitempty"`
+}
+
+// resolve parses the chat JID and works out the sender JID of the referenced message.
+// An empty sender JID stands for this account.
+func (ref MessageRef) resolve() (chat types.JID, sender types.JID, err error) {
+	chat, err = ParseJID(ref.Chat)
+	if err != nil {
+		return chat, sender, fmt.Errorf("invalid chat: %v", err)
+	}
+	if ref.MessageID == "" {
+		return chat, sender, fmt.Errorf("message ID is required")
+	}
+
+	switch {
+	case ref.FromMe:
+		sender = types.EmptyJID
+	case ref.Sender != "":
+		sender, err = ParseJID(ref.Sender)
+		if err != nil {
+			return chat, sender, fmt.Errorf("invalid sender: %v", err)
+		}
+	case chat.Server == types.GroupServer:
+		return chat, sender, fmt.Errorf("sender is required for messages in groups")
+	default:
+		sender = chat
+	}
+	return chat, sender, nil
+}
+
+// SendReaction reacts to a message with an emoji. An empty emoji removes the reaction.
+func (c *Client) SendReaction(ref MessageRef, emoji string) (*SendResult, error) {
+	chat, sender, err := ref.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	msg := c.Client.BuildReaction(chat, sender, ref.MessageID, emoji)
+	return c.sendMessage(chat, msg, SendOptions{})
+}
@@ -0,0 +1,170 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/w33ladalah/whrabbit/internal/api/websocket"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Bounds of the delay between reconnect attempts, which doubles after every failure
+const (
+	reconnectMinDelay = 2 * time.Second
+	reconnectMaxDelay = 5 * time.Minute
+)
+
+// logoutTimeout is how long to wait for whatsmeow to delete the device after a logout
+const logoutTimeout = 30 * time.Second
+
+// handleConnectionEvent keeps the session connected. Transient disconnects are retried
+// with backoff, a logout starts a new QR login, and failures that reconnecting can't fix
+// are only reported. Every change is sent to WebSocket clients as a status frame.
+func (c *Client) handleConnectionEvent(evt interface{}) {
+	switch v := evt.(type) {
+	case *events.Connected:
+		log.Printf("Session %s connected to WhatsApp", c.sessionID)
+		c.connectedAtMux.Lock()
+		c.connectedAt = time.Now().UTC()
+		c.connectedAtMux.Unlock()
+		c.stopReconnect()
+		c.wsManager.BroadcastConnectionStatus(websocket.StateConnected, "WhatsApp connected successfully!")
+	case *events.Disconnected:
+		log.Printf("Session %s disconnected from WhatsApp", c.sessionID)
+		if c.Store.ID == nil {
+			// There is nothing to reconnect to, the running QR login starts a new one
+			// when it sees its connection end
+			c.loginDropped.Store(true)
+			return
+		}
+		// The device is kept, so the session picks up where it left off
+		c.startReconnect(0)
+	case *events.LoggedOut:
+		// whatsmeow deletes the device, a new one has to be paired
+		log.Printf("Session %s was logged out: %s", c.sessionID, v.Reason)
+		c.stopReconnect()
+		c.wsManager.BroadcastConnectionStatus(websocket.StateLoggedOut, fmt.Sprintf("WhatsApp logged out: %s", v.Reason))
+		go c.loginAfterLogout()
+	case *events.StreamReplaced:
+		// Reconnecting would take the connection back from the other client
+		log.Printf("Session %s was opened elsewhere", c.sessionID)
+		c.stopReconnect()
+		c.wsManager.BroadcastConnectionStatus(websocket.StateReplaced, "WhatsApp session was opened elsewhere, not reconnecting")
+	case *events.TemporaryBan:
+		log.Printf("Session %s is temporarily banned: %s", c.sessionID, v)
+		c.wsManager.BroadcastConnectionStatus(websocket.StateBanned, fmt.Sprintf("WhatsApp account is temporarily banned: %s", v))
+		if v.Expire > 0 {
+			c.startReconnect(v.Expire)
+		}
+	case *events.ClientOutdated:
+		log.Printf("Session %s uses an outdated WhatsApp client version", c.sessionID)
+		c.stopReconnect()
+		c.wsManager.BroadcastConnectionStatus(websocket.StateOutdated, "WhatsApp client is outdated, update whrabbit to reconnect")
+	case *events.ConnectFailure:
+		log.Printf("Session %s failed to connect: %s %s", c.sessionID, v.Reason, v.Message)
+		c.startReconnect(0)
+	}
+}
+
+// startReconnect reconnects in the background after wait, or after the next backoff
+// delay when wait is zero. Only backoff delays are announced as reconnecting. A running
+// reconnect loop is replaced.
+func (c *Client) startReconnect(wait time.Duration) {
+	c.reconnectMux.Lock()
+	defer c.reconnectMux.Unlock()
+	if c.reconnectCancel != nil {
+		c.reconnectCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.reconnectCancel = cancel
+	go c.reconnect(ctx, wait)
+}
+
+// stopReconnect stops the reconnect loop, if any, and resets the backoff
+func (c *Client) stopReconnect() {
+	c.reconnectMux.Lock()
+	defer c.reconnectMux.Unlock()
+	if c.reconnectCancel != nil {
+		c.reconnectCancel()
+		c.reconnectCancel = nil
+	}
+	c.reconnectAttempts = 0
+}
+
+// reconnect tries to connect until the socket is up. Whether the login succeeds is
+// reported by the Connected, LoggedOut and other connection events.
+func (c *Client) reconnect(ctx context.Context, wait time.Duration) {
+	for {
+		// Sessions that aren't paired log in with a QR code instead
+		if c.Store.ID == nil {
+			return
+		}
+		// A given wait is the rest of a ban, whose status stays until it expires
+		if wait <= 0 {
+			wait = c.nextReconnectDelay()
+			c.wsManager.BroadcastConnectionStatus(websocket.StateReconnecting, fmt.Sprintf("WhatsApp disconnected, reconnecting in %s", wait.Round(time.Second)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		// A logout may have removed the device in the meantime
+		if c.Store.ID == nil {
+			return
+		}
+		err := c.Client.Connect()
+		if err == nil || errors.Is(err, whatsmeow.ErrAlreadyConnected) {
+			return
+		}
+		log.Printf("Error reconnecting session %s to WhatsApp: %v", c.sessionID, err)
+		wait = 0
+	}
+}
+
+// nextReconnectDelay returns the exponential backoff delay of the next attempt with
+// jitter, so sessions that dropped together don't reconnect in lockstep
+func (c *Client) nextReconnectDelay() time.Duration {
+	c.reconnectMux.Lock()
+	attempt := c.reconnectAttempts
+	c.reconnectAttempts++
+	c.reconnectMux.Unlock()
+
+	delay := reconnectMaxDelay
+	if attempt < 16 && reconnectMinDelay<<attempt < reconnectMaxDelay {
+		delay = reconnectMinDelay << attempt
+	}
+	// Wait between half and all of the delay
+	return delay/2 + rand.N(delay/2)
+}
+
+// loginAfterLogout starts a new QR login once whatsmeow has deleted the device and closed
+// the connection, which it does while the LoggedOut event is being dispatched
+func (c *Client) loginAfterLogout() {
+	deadline := time.Now().Add(logoutTimeout)
+	for c.Store.ID != nil || c.IsConnected() {
+		if time.Now().After(deadline) {
+			log.Printf("Session %s still has a device after logging out, not starting a new login", c.sessionID)
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	c.connectInBackground()
+}
+
+// connectInBackground connects the client without waiting, starting a QR login when the
+// session isn't paired
+func (c *Client) connectInBackground() {
+	go func() {
+		if err := c.Connect(context.Background()); err != nil {
+			log.Printf("Error connecting session %s to WhatsApp: %v", c.sessionID, err)
+		}
+	}()
+}
@@ -0,0 +1,181 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// SendOptions holds the options shared by all send methods
+type SendOptions struct {
+	// ReplyTo quotes an earlier message when set
+	ReplyTo *ReplyTo
+}
+
+// ReplyTo identifies the message quoted by a reply
+type ReplyTo struct {
+	MessageID string `json:"message_id" binding:"required" example:"3EB0C431C26A1916E5F6"`
+	// Sender is the JID or phone number of the quoted message's author.
+	// Required in groups, defaults to the recipient in private chats.
+	Sender string `json:"sender,omitempty" example:"1234567890"`
+	// Text is shown in the quote preview
+	Text string `json:"text,omitempty" example:"Where is my order?"`
+}
+
+// SendResult describes a message accepted by the WhatsApp server
+type SendResult struct {
+	// ID is the message ID, used to reply to, react to, edit or revoke the message
+	ID        string    `json:"id" example:"3EB0C431C26A1916E5F6"`
+	Timestamp time.Time `json:"timestamp" example:"2025-05-01T13:06:09Z"`
+	Recipient string    `json:"recipient" example:"1234567890@s.whatsapp.net"`
+	// ServerID is only set for newsletter messages
+	ServerID     int          `json:"server_id,omitempty"`
+	DebugTimings DebugTimings `json:"debug_timings"`
+}
+
+// DebugTimings breaks down how long each step of sending a message took
+type DebugTimings struct {
+	Queue           Duration `json:"queue" swaggertype:"string" example:"1.2ms"`
+	Marshal         Duration `json:"marshal" swaggertype:"string" example:"1.2ms"`
+	GetParticipants Duration `json:"get_participants" swaggertype:"string" example:"1.2ms"`
+	GetDevices      Duration `json:"get_devices" swaggertype:"string" example:"1.2ms"`
+	GroupEncrypt    Duration `json:"group_encrypt" swaggertype:"string" example:"1.2ms"`
+	PeerEncrypt     Duration `json:"peer_encrypt" swaggertype:"string" example:"1.2ms"`
+	Send            Duration `json:"send" swaggertype:"string" example:"1.2ms"`
+	Resp            Duration `json:"resp" swaggertype:"string" example:"1.2ms"`
+	Retry           Duration `json:"retry" swaggertype:"string" example:"1.2ms"`
+}
+
+// Duration is a time.Duration that is encoded in JSON as a string such as "1.5ms"
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func newSendResult(recipient types.JID, resp whatsmeow.SendResponse) *SendResult {
+	timings := resp.DebugTimings
+	return &SendResult{
+		ID:        resp.ID,
+		Timestamp: resp.Timestamp,
+		Recipient: recipient.String(),
+		ServerID:  resp.ServerID,
+		DebugTimings: DebugTimings{
+			Queue:           Duration(timings.Queue),
+			Marshal:         Duration(timings.Marshal),
+			GetParticipants: Duration(timings.GetParticipants),
+			GetDevices:      Duration(timings.GetDevices),
+			GroupEncrypt:    Duration(timings.GroupEncrypt),
+			PeerEncrypt:     Duration(timings.PeerEncrypt),
+			Send:            Duration(timings.Send),
+			Resp:            Duration(timings.Resp),
+			Retry:           Duration(timings.Retry),
+		},
+	}
+}
+
+// sendMessage applies the send options to msg and sends it to the recipient
+func (c *Client) sendMessage(recipient types.JID, msg *waProto.Message, opts SendOptions) (*SendResult, error) {
+	contextInfo, err := buildContextInfo(recipient, opts)
+	if err != nil {
+		return nil, err
+	}
+	if contextInfo != nil {
+		setContextInfo(msg, contextInfo)
+	}
+
+	resp, err := c.Client.SendMessage(context.Background(), recipient, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sent.add(resp.ID, recipient, msg, resp.Timestamp)
+	sender := types.EmptyJID
+	if c.Store.ID != nil {
+		sender = *c.Store.ID
+	}
+	c.storeMessage(resp.ID, recipient, sender, true, resp.Timestamp, msg)
+	return newSendResult(recipient, resp), nil
+}
+
+// buildContextInfo returns the context info for the send options, or nil if none is needed
+func buildContextInfo(recipient types.JID, opts SendOptions) (*waProto.ContextInfo, error) {
+	if opts.ReplyTo == nil {
+		return nil, nil
+	}
+
+	reply := opts.ReplyTo
+	if reply.MessageID == "" {
+		return nil, fmt.Errorf("reply_to message ID is required")
+	}
+
+	participant := recipient
+	if reply.Sender != "" {
+		sender, err := ParseJID(reply.Sender)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reply_to sender: %v", err)
+		}
+		participant = sender
+	} else if recipient.Server == types.GroupServer {
+		return nil, fmt.Errorf("reply_to sender is required when replying in a group")
+	}
+
+	return &waProto.ContextInfo{
+		StanzaID:    proto.String(reply.MessageID),
+		Participant: proto.String(participant.ToNonAD().String()),
+		QuotedMessage: &waProto.Message{
+			Conversation: proto.String(reply.Text),
+		},
+	}, nil
+}
+
+// setContextInfo attaches context info to whichever content msg carries, merging it
+// with any context info already set. Plain text is upgraded to an extended text
+// message, which is the only text type with context info.
+func setContextInfo(msg *waProto.Message, contextInfo *waProto.ContextInfo) {
+	switch {
+	case msg.Conversation != nil:
+		msg.ExtendedTextMessage = &waProto.ExtendedTextMessage{
+			Text:        msg.Conversation,
+			ContextInfo: contextInfo,
+		}
+		msg.Conversation = nil
+	case msg.ExtendedTextMessage != nil:
+		msg.ExtendedTextMessage.ContextInfo = mergeContextInfo(msg.ExtendedTextMessage.ContextInfo, contextInfo)
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.ContextInfo = mergeContextInfo(msg.ImageMessage.ContextInfo, contextInfo)
+	case msg.VideoMessage != nil:
+		msg.VideoMessage.ContextInfo = mergeContextInfo(msg.VideoMessage.ContextInfo, contextInfo)
+	case msg.DocumentMessage != nil:
+		msg.DocumentMessage.ContextInfo = mergeContextInfo(msg.DocumentMessage.ContextInfo, contextInfo)
+	case msg.AudioMessage != nil:
+		msg.AudioMessage.ContextInfo = mergeContextInfo(msg.AudioMessage.ContextInfo, contextInfo)
+	case msg.StickerMessage != nil:
+		msg.StickerMessage.ContextInfo = mergeContextInfo(msg.StickerMessage.ContextInfo, contextInfo)
+	case msg.LocationMessage != nil:
+		msg.LocationMessage.ContextInfo = mergeContextInfo(msg.LocationMessage.ContextInfo, contextInfo)
+	case msg.LiveLocationMessage != nil:
+		msg.LiveLocationMessage.ContextInfo = mergeContextInfo(msg.LiveLocationMessage.ContextInfo, contextInfo)
+	case msg.ContactMessage != nil:
+		msg.ContactMessage.ContextInfo = mergeContextInfo(msg.ContactMessage.ContextInfo, contextInfo)
+	case msg.ContactsArrayMessage != nil:
+		msg.ContactsArrayMessage.ContextInfo = mergeContextInfo(msg.ContactsArrayMessage.ContextInfo, contextInfo)
+	case msg.PollCreationMessage != nil:
+		msg.PollCreationMessage.ContextInfo = mergeContextInfo(msg.PollCreationMessage.ContextInfo, contextInfo)
+	}
+}
+
+// mergeContextInfo copies the fields set in contextInfo onto existing
+func mergeContextInfo(existing, contextInfo *waProto.ContextInfo) *waProto.ContextInfo {
+	if existing == nil {
+		return contextInfo
+	}
+	proto.Merge(existing, contextInfo)
+	return existing
+}
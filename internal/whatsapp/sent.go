@@ -0,0 +1,61 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// sentRetention is how long sent messages are remembered for edits and revokes
+const sentRetention = 48 * time.Hour
+
+// sentMessage is a message sent through this client
+type sentMessage struct {
+	chat      types.JID
+	timestamp time.Time
+	// isText is true for text messages, the only kind that can be edited
+	isText bool
+}
+
+// sentStore remembers recently sent messages by ID
+type sentStore struct {
+	messages map[types.MessageID]sentMessage
+	mux      sync.RWMutex
+}
+
+func newSentStore() *sentStore {
+	return &sentStore{
+		messages: make(map[types.MessageID]sentMessage),
+	}
+}
+
+// add records a sent message and forgets messages older than sentRetention.
+// Reactions, edits and revokes are not recorded.
+func (s *sentStore) add(id types.MessageID, chat types.JID, msg *waProto.Message, timestamp time.Time) {
+	if msg.ProtocolMessage != nil || msg.EditedMessage != nil || msg.ReactionMessage != nil {
+		return
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for oldID, old := range s.messages {
+		if time.Since(old.timestamp) > sentRetention {
+			delete(s.messages, oldID)
+		}
+	}
+	s.messages[id] = sentMessage{
+		chat:      chat,
+		timestamp: timestamp,
+		isText:    msg.Conversation != nil || msg.ExtendedTextMessage != nil,
+	}
+}
+
+func (s *sentStore) get(id types.MessageID) (sentMessage, bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	msg, ok := s.messages[id]
+	return msg, ok
+}
@@ -0,0 +1,368 @@
+package whatsapp
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/w33ladalah/whrabbit/internal/storage"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// DefaultSessionID is the session used by the routes that don't name one
+const DefaultSessionID = "default"
+
+// Session errors
+var (
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrSessionExists    = errors.New("session already exists")
+	ErrInvalidSessionID = errors.New("session ID may only contain letters, digits, '-' and '_' and be at most 64 characters")
+)
+
+// sessionIDPattern matches the session IDs that can be used in a URL path
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+const sessionSchema = `
+CREATE TABLE IF NOT EXISTS whrabbit_sessions (
+	id         TEXT    PRIMARY KEY,
+	jid        TEXT    NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL
+);
+`
+
+// SessionInfo describes a session
+type SessionInfo struct {
+	ID string `json:"id" example:"sales"`
+	// JID is the linked WhatsApp account, empty until the session is paired
+	JID       string    `json:"jid,omitempty" example:"6281234567890:12@s.whatsapp.net"`
+	Connected bool      `json:"connected"`
+	LoggedIn  bool      `json:"logged_in"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// session is a client and the time its session was created
+type session struct {
+	client    *Client
+	createdAt time.Time
+	// deleting is set while the session is being deleted
+	deleting bool
+}
+
+// SessionManager runs one client per WhatsApp account. Every session has its own device
+// in the whatsmeow database and the session IDs are kept next to them, so sessions
+// survive a restart.
+type SessionManager struct {
+	container   *sqlstore.Container
+	db          *sql.DB
+	sessions    map[string]*session
+	sessionsMux sync.RWMutex
+	messages    storage.MessageStore
+	sinks       []EventSink
+}
+
+// NewSessionManager opens the device database at dbPath and loads the saved sessions.
+// A database from before sessions existed keeps its device as the default session,
+// and the default session is created when there are no sessions at all.
+func NewSessionManager(dbPath string) (*SessionManager, error) {
+	container, err := newContainer(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("error opening session database: %v", err)
+	}
+	if _, err := db.Exec(sessionSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating session table: %v", err)
+	}
+
+	m := &SessionManager{
+		container: container,
+		db:        db,
+		sessions:  make(map[string]*session),
+	}
+	if err := m.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// load creates a client for every saved session
+func (m *SessionManager) load() error {
+	rows, err := m.db.Query("SELECT id, jid, created_at FROM whrabbit_sessions")
+	if err != nil {
+		return fmt.Errorf("error loading sessions: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, jid string
+		var createdAt int64
+		if err := rows.Scan(&id, &jid, &createdAt); err != nil {
+			return fmt.Errorf("error reading session: %v", err)
+		}
+		deviceStore, err := m.device(jid)
+		if err != nil {
+			return fmt.Errorf("error loading device of session %s: %v", id, err)
+		}
+		m.add(id, deviceStore, time.UnixMilli(createdAt).UTC())
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error loading sessions: %v", err)
+	}
+
+	if len(m.sessions) > 0 {
+		return nil
+	}
+
+	// Adopt the device of a single account database
+	deviceStore, err := m.container.GetFirstDevice()
+	if err != nil {
+		return fmt.Errorf("error getting device store: %v", err)
+	}
+	_, err = m.insert(DefaultSessionID, deviceStore)
+	return err
+}
+
+// device returns the stored device with the given JID, or a new device when the
+// session was never paired or its device has been removed
+func (m *SessionManager) device(jid string) (*store.Device, error) {
+	if jid == "" {
+		return m.container.NewDevice(), nil
+	}
+	parsed, err := types.ParseJID(jid)
+	if err != nil {
+		return nil, err
+	}
+	deviceStore, err := m.container.GetDevice(parsed)
+	if err != nil {
+		return nil, err
+	}
+	if deviceStore == nil {
+		return m.container.NewDevice(), nil
+	}
+	return deviceStore, nil
+}
+
+// insert saves a new session and creates its client
+func (m *SessionManager) insert(id string, deviceStore *store.Device) (*Client, error) {
+	jid := ""
+	if deviceStore.ID != nil {
+		jid = deviceStore.ID.String()
+	}
+	createdAt := time.Now().UTC()
+	if _, err := m.db.Exec("INSERT INTO whrabbit_sessions (id, jid, created_at) VALUES (?, ?, ?)", id, jid, createdAt.UnixMilli()); err != nil {
+		return nil, fmt.Errorf("error saving session: %v", err)
+	}
+	return m.add(id, deviceStore, createdAt), nil
+}
+
+// add creates the client of a session and wires it to the shared message store and sinks
+func (m *SessionManager) add(id string, deviceStore *store.Device, createdAt time.Time) *Client {
+	client := newClient(id, deviceStore)
+	client.SetMessageStore(m.messages)
+	for _, sink := range m.sinks {
+		client.AddEventSink(sink)
+	}
+
+	// Remember which device belongs to the session once it is paired
+	client.AddEventHandler(func(evt interface{}) {
+		switch v := evt.(type) {
+		case *events.PairSuccess:
+			m.saveJID(id, v.ID.String())
+		case *events.LoggedOut:
+			m.saveJID(id, "")
+		}
+	})
+
+	m.sessions[id] = &session{client: client, createdAt: createdAt}
+	return client
+}
+
+// saveJID records the device of a session
+func (m *SessionManager) saveJID(id, jid string) {
+	if _, err := m.db.Exec("UPDATE whrabbit_sessions SET jid = ? WHERE id = ?", jid, id); err != nil {
+		log.Printf("Error saving device of session %s: %v", id, err)
+	}
+}
+
+// SetMessageStore sets the message store of every session, including those created later
+func (m *SessionManager) SetMessageStore(messageStore storage.MessageStore) {
+	m.sessionsMux.Lock()
+	defer m.sessionsMux.Unlock()
+	m.messages = messageStore
+	for _, s := range m.sessions {
+		s.client.SetMessageStore(messageStore)
+	}
+}
+
+// AddEventSink registers a sink that receives the events of every session, including
+// those created later
+func (m *SessionManager) AddEventSink(sink EventSink) {
+	m.sessionsMux.Lock()
+	defer m.sessionsMux.Unlock()
+	m.sinks = append(m.sinks, sink)
+	for _, s := range m.sessions {
+		s.client.AddEventSink(sink)
+	}
+}
+
+// Create adds a session with a new device and starts its QR login.
+// A random ID is generated when id is empty.
+func (m *SessionManager) Create(id string) (*Client, error) {
+	if id == "" {
+		id = newSessionID()
+	}
+	if !sessionIDPattern.MatchString(id) {
+		return nil, ErrInvalidSessionID
+	}
+
+	m.sessionsMux.Lock()
+	if _, ok := m.sessions[id]; ok {
+		m.sessionsMux.Unlock()
+		return nil, ErrSessionExists
+	}
+	client, err := m.insert(id, m.container.NewDevice())
+	m.sessionsMux.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	client.connectInBackground()
+	return client, nil
+}
+
+// Get returns the client of a session
+func (m *SessionManager) Get(id string) (*Client, bool) {
+	m.sessionsMux.RLock()
+	defer m.sessionsMux.RUnlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	return s.client, true
+}
+
+// Info describes a session
+func (m *SessionManager) Info(id string) (SessionInfo, error) {
+	m.sessionsMux.RLock()
+	defer m.sessionsMux.RUnlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return SessionInfo{}, ErrSessionNotFound
+	}
+	return s.info(id), nil
+}
+
+// List describes every session, oldest first
+func (m *SessionManager) List() []SessionInfo {
+	m.sessionsMux.RLock()
+	infos := make([]SessionInfo, 0, len(m.sessions))
+	for id, s := range m.sessions {
+		infos = append(infos, s.info(id))
+	}
+	m.sessionsMux.RUnlock()
+
+	slices.SortFunc(infos, func(a, b SessionInfo) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return infos
+}
+
+// info describes the session
+func (s *session) info(id string) SessionInfo {
+	info := SessionInfo{
+		ID:        id,
+		Connected: s.client.IsConnected(),
+		LoggedIn:  s.client.IsLoggedIn(),
+		CreatedAt: s.createdAt,
+	}
+	if s.client.Store.ID != nil {
+		info.JID = s.client.Store.ID.String()
+	}
+	return info
+}
+
+// Delete logs a session out of WhatsApp, removes its device and forgets the session.
+// The session keeps being served until it is gone, so it is still there when deleting fails.
+func (m *SessionManager) Delete(id string) error {
+	m.sessionsMux.Lock()
+	s, ok := m.sessions[id]
+	if !ok || s.deleting {
+		m.sessionsMux.Unlock()
+		return ErrSessionNotFound
+	}
+	s.deleting = true
+	m.sessionsMux.Unlock()
+
+	// Logging out can take a while, don't block other sessions meanwhile
+	if err := m.remove(id, s.client); err != nil {
+		m.sessionsMux.Lock()
+		s.deleting = false
+		m.sessionsMux.Unlock()
+		return err
+	}
+
+	m.sessionsMux.Lock()
+	delete(m.sessions, id)
+	m.sessionsMux.Unlock()
+
+	// Stop talking to the session's WebSocket clients
+	s.client.wsManager.Close()
+	return nil
+}
+
+// remove logs a session out, deletes its device and its row
+func (m *SessionManager) remove(id string, client *Client) error {
+	client.endLogin()
+	client.stopReconnect()
+	if client.IsLoggedIn() {
+		if err := client.Client.Logout(); err != nil {
+			log.Printf("Error logging out session %s: %v", id, err)
+		}
+	}
+	client.Client.Disconnect()
+	if client.Store.ID != nil {
+		if err := client.Store.Delete(); err != nil {
+			return fmt.Errorf("error deleting device: %v", err)
+		}
+	}
+
+	if _, err := m.db.Exec("DELETE FROM whrabbit_sessions WHERE id = ?", id); err != nil {
+		return fmt.Errorf("error deleting session: %v", err)
+	}
+	return nil
+}
+
+// ConnectAll connects every session in the background
+func (m *SessionManager) ConnectAll() {
+	m.sessionsMux.RLock()
+	defer m.sessionsMux.RUnlock()
+	for _, s := range m.sessions {
+		s.client.connectInBackground()
+	}
+}
+
+// newSessionID returns a random session ID
+func newSessionID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
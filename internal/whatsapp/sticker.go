@@ -0,0 +1,106 @@
+package whatsapp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"golang.org/x/image/draw"
+	"google.golang.org/protobuf/proto"
+)
+
+// stickerSize is the width and height in pixels WhatsApp expects stickers to have
+const stickerSize = 512
+
+// SendSticker sends a sticker to a WhatsApp number. PNG, JPEG and WebP images are
+// accepted. Anything but a 512x512 WebP is scaled to fit a transparent 512x512 canvas
+// and converted to WebP with ffmpeg.
+func (c *Client) SendSticker(to string, sticker io.Reader, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	// Read sticker data
+	stickerData, err := io.ReadAll(sticker)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sticker: %v", err)
+	}
+
+	mimeType := detectMimeType(stickerData)
+	if mimeType != "image/png" && mimeType != "image/jpeg" && mimeType != "image/webp" {
+		return nil, fmt.Errorf("unsupported sticker type: %s", mimeType)
+	}
+
+	img, err := decodeImage(stickerData)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	if mimeType != "image/webp" || bounds.Dx() != stickerSize || bounds.Dy() != stickerSize {
+		stickerData, err = convertSticker(img)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Upload sticker to WhatsApp
+	uploaded, err := c.uploadMedia(stickerData, whatsmeow.MediaImage)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading sticker: %v", err)
+	}
+
+	msg := &waProto.Message{
+		StickerMessage: &waProto.StickerMessage{
+			URL:           &uploaded.URL,
+			Mimetype:      proto.String("image/webp"),
+			FileSHA256:    uploaded.FileSHA256,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileLength:    &uploaded.FileLength,
+			MediaKey:      uploaded.MediaKey,
+			DirectPath:    &uploaded.DirectPath,
+			Width:         proto.Uint32(stickerSize),
+			Height:        proto.Uint32(stickerSize),
+			IsAnimated:    proto.Bool(false),
+		},
+	}
+
+	return c.sendMessage(recipient, msg, opts)
+}
+
+// convertSticker scales an image to fit a transparent 512x512 canvas, keeping its
+// aspect ratio, and encodes it as WebP with ffmpeg
+func convertSticker(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, fmt.Errorf("image has no pixels")
+	}
+
+	width, height := stickerSize, stickerSize
+	if bounds.Dx() > bounds.Dy() {
+		height = max(bounds.Dy()*stickerSize/bounds.Dx(), 1)
+	} else {
+		width = max(bounds.Dx()*stickerSize/bounds.Dy(), 1)
+	}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, stickerSize, stickerSize))
+	offset := image.Pt((stickerSize-width)/2, (stickerSize-height)/2)
+	draw.CatmullRom.Scale(canvas, image.Rectangle{Min: offset, Max: offset.Add(image.Pt(width, height))}, img, bounds, draw.Src, nil)
+
+	// ffmpeg can't read Go images directly, so hand it a lossless PNG
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, fmt.Errorf("error encoding sticker: %v", err)
+	}
+
+	webp, err := runFFmpeg(buf.Bytes(), "-c:v", "libwebp", "-quality", "80", "-f", "webp", "pipe:1")
+	if err != nil {
+		return nil, fmt.Errorf("error converting sticker to WebP: %v", err)
+	}
+	return webp, nil
+}
@@ -0,0 +1,178 @@
+package whatsapp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// SendVideo sends a video message to a WhatsApp number
+func (c *Client) SendVideo(to string, video io.Reader, caption string, opts SendOptions) (*SendResult, error) {
+	recipient, err := ParseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient number: %v", err)
+	}
+
+	// Read video data
+	videoData, err := io.ReadAll(video)
+	if err != nil {
+		return nil, fmt.Errorf("error reading video: %v", err)
+	}
+
+	mimeType := detectMimeType(videoData)
+	if !strings.HasPrefix(mimeType, "video/") {
+		return nil, fmt.Errorf("unsupported video type: %s", mimeType)
+	}
+
+	// Upload video to WhatsApp
+	uploaded, err := c.uploadMedia(videoData, whatsmeow.MediaVideo)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading video: %v", err)
+	}
+
+	info := probeMP4(videoData)
+
+	thumbnail, err := videoThumbnail(videoData)
+	if err != nil {
+		log.Printf("Could not generate video thumbnail: %v", err)
+	}
+
+	msg := &waProto.Message{
+		VideoMessage: &waProto.VideoMessage{
+			URL:           &uploaded.URL,
+			Mimetype:      proto.String(mimeType),
+			Caption:       proto.String(caption),
+			FileSHA256:    uploaded.FileSHA256,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileLength:    &uploaded.FileLength,
+			MediaKey:      uploaded.MediaKey,
+			DirectPath:    &uploaded.DirectPath,
+			Seconds:       proto.Uint32(info.Seconds),
+			JPEGThumbnail: thumbnail,
+		},
+	}
+	if info.Width > 0 && info.Height > 0 {
+		msg.VideoMessage.Width = proto.Uint32(info.Width)
+		msg.VideoMessage.Height = proto.Uint32(info.Height)
+	}
+
+	return c.sendMessage(recipient, msg, opts)
+}
+
+// videoThumbnail extracts the first frame of a video as a small JPEG using ffmpeg
+func videoThumbnail(data []byte) ([]byte, error) {
+	thumb, err := runFFmpeg(data,
+		"-frames:v", "1",
+		"-vf", "scale="+strconv.Itoa(thumbnailWidth)+":-2",
+		"-f", "image2", "-c:v", "mjpeg", "pipe:1",
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(thumb) == 0 {
+		return nil, fmt.Errorf("ffmpeg produced an empty thumbnail")
+	}
+	return thumb, nil
+}
+
+// mp4Info holds the metadata read from an MP4/MOV container
+type mp4Info struct {
+	Seconds uint32
+	Width   uint32
+	Height  uint32
+}
+
+// probeMP4 reads the duration and video dimensions from the moov box of an
+// MP4/MOV file. Fields that can't be found are left as zero.
+func probeMP4(data []byte) mp4Info {
+	var info mp4Info
+
+	moov := findBox(data, "moov")
+	if moov == nil {
+		return info
+	}
+
+	if mvhd := findBox(moov, "mvhd"); len(mvhd) >= 4 {
+		var timescale, duration uint64
+		if mvhd[0] == 1 && len(mvhd) >= 32 {
+			timescale = uint64(binary.BigEndian.Uint32(mvhd[20:24]))
+			duration = binary.BigEndian.Uint64(mvhd[24:32])
+		} else if len(mvhd) >= 20 {
+			timescale = uint64(binary.BigEndian.Uint32(mvhd[12:16]))
+			duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+		}
+		if timescale > 0 {
+			info.Seconds = uint32((duration + timescale/2) / timescale)
+		}
+	}
+
+	// Use the first track with non-zero dimensions, audio tracks have none
+	for _, trak := range findBoxes(moov, "trak") {
+		tkhd := findBox(trak, "tkhd")
+		if len(tkhd) < 4 {
+			continue
+		}
+		offset := 76
+		if tkhd[0] == 1 {
+			offset = 88
+		}
+		if len(tkhd) < offset+8 {
+			continue
+		}
+		// Width and height are 16.16 fixed-point numbers
+		width := binary.BigEndian.Uint32(tkhd[offset:offset+4]) >> 16
+		height := binary.BigEndian.Uint32(tkhd[offset+4:offset+8]) >> 16
+		if width > 0 && height > 0 {
+			info.Width = width
+			info.Height = height
+			break
+		}
+	}
+
+	return info
+}
+
+// findBox returns the payload of the first box with the given type
+func findBox(data []byte, boxType string) []byte {
+	boxes := findBoxes(data, boxType)
+	if len(boxes) == 0 {
+		return nil
+	}
+	return boxes[0]
+}
+
+// findBoxes returns the payloads of all top-level boxes with the given type
+func findBoxes(data []byte, boxType string) [][]byte {
+	var boxes [][]byte
+	for len(data) >= 8 {
+		size := uint64(binary.BigEndian.Uint32(data[0:4]))
+		name := string(data[4:8])
+		header := uint64(8)
+		switch size {
+		case 0:
+			// Box extends to the end of the data
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return boxes
+			}
+			size = binary.BigEndian.Uint64(data[8:16])
+			header = 16
+		}
+		if size < header || size > uint64(len(data)) {
+			return boxes
+		}
+		if name == boxType {
+			boxes = append(boxes, data[header:size])
+		}
+		data = data[size:]
+	}
+	return boxes
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/w33ladalah/whrabbit/internal/api/handlers"
 	"github.com/w33ladalah/whrabbit/internal/api/middleware"
 	"github.com/w33ladalah/whrabbit/internal/config"
+	"github.com/w33ladalah/whrabbit/internal/storage"
+	"github.com/w33ladalah/whrabbit/internal/webhook"
 	"github.com/w33ladalah/whrabbit/internal/whatsapp"
 )
 
@@ -48,21 +50,44 @@ func main() {
 		fmt.Println("Error loading environment variables:", err)
 	}
 
-	// Initialize WhatsApp client
-	client, err := whatsapp.NewClient("whatsmeow.db")
+	// Load the WhatsApp sessions
+	sessions, err := whatsapp.NewSessionManager("whatsmeow.db")
 	if err != nil {
-		log.Fatalf("Error creating WhatsApp client: %v", err)
+		log.Fatalf("Error loading WhatsApp sessions: %v", err)
 	}
 
+	// Open the message store next to the WhatsApp session database
+	messageStore, err := storage.NewSQLiteMessageStore("messages.db")
+	if err != nil {
+		log.Fatalf("Error opening message store: %v", err)
+	}
+	defer messageStore.Close()
+	sessions.SetMessageStore(messageStore)
+
+	// Deliver events to the webhooks from the configuration
+	dispatcher := webhook.NewDispatcher(config.GetWebhookTimeout(), config.GetWebhookMaxAttempts())
+	for _, url := range config.GetWebhookURLs() {
+		if _, err := dispatcher.Register(url, config.GetWebhookSecret(), nil); err != nil {
+			log.Fatalf("Error registering webhook: %v", err)
+		}
+	}
+	sessions.AddEventSink(dispatcher)
+
 	// Create WebSocket handler
-	wsHandler := handlers.NewWebSocketHandler()
-	client.SetWebSocketManager(wsHandler.GetManager())
+	wsHandler := handlers.NewSessionWebSocketHandler(sessions)
 
 	// Create message handler
-	msgHandler := handlers.NewMessageHandler(client)
+	msgHandler := handlers.NewSessionMessageHandler(sessions)
+
+	// Create session handler
+	sessionHandler := handlers.NewSessionHandler(sessions)
 
-	// Initialize router
-	router := gin.Default()
+	// Create webhook handler
+	webhookHandler := handlers.NewWebhookHandler(dispatcher)
+
+	// Initialize router, keeping API keys passed to /ws out of the access log
+	router := gin.New()
+	router.Use(middleware.Logger(), gin.Recovery())
 
 	fmt.Println("Base URL:", config.GetBaseURL())
 
@@ -84,9 +109,23 @@ func main() {
 	api := router.Group("/api/v1")
 	api.Use(middleware.APIKeyAuth())
 	{
-		// Message routes
-		api.POST("/messages/text", msgHandler.SendText)
-		api.POST("/messages/image", msgHandler.SendImage)
+		// Session routes
+		api.GET("/sessions", sessionHandler.ListSessions)
+		api.POST("/sessions", sessionHandler.CreateSession)
+		api.GET("/sessions/:session", sessionHandler.GetSession)
+		api.DELETE("/sessions/:session", sessionHandler.DeleteSession)
+
+		// Routes of the default session
+		registerSessionRoutes(api.Group("", msgHandler.ResolveSession), msgHandler)
+
+		// The same routes for a named session
+		registerSessionRoutes(api.Group("/sessions/:session", msgHandler.ResolveSession), msgHandler)
+
+		// Webhook routes
+		api.GET("/webhooks", webhookHandler.ListWebhooks)
+		api.POST("/webhooks", webhookHandler.CreateWebhook)
+		api.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+		api.GET("/webhooks/dead-letters", webhookHandler.ListDeadLetters)
 	}
 
 	// Swagger UI
@@ -111,12 +150,8 @@ func main() {
 		}
 	}()
 
-	// Connect to WhatsApp
-	go func() {
-		if err := client.Connect(context.Background()); err != nil {
-			log.Printf("Error connecting to WhatsApp: %v", err)
-		}
-	}()
+	// Connect every session to WhatsApp
+	sessions.ConnectAll()
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
@@ -135,3 +170,39 @@ func main() {
 
 	log.Println("Server exiting")
 }
+
+// registerSessionRoutes adds the routes that act on a single WhatsApp session
+func registerSessionRoutes(group *gin.RouterGroup, msgHandler *handlers.MessageHandler) {
+	// Login routes
+	group.GET("/session/status", msgHandler.GetSessionStatus)
+	group.GET("/session/qr", msgHandler.GetQR)
+	group.GET("/session/qr.png", msgHandler.GetQRPNG)
+	group.GET("/session/qr.svg", msgHandler.GetQRSVG)
+	group.POST("/session/pair-phone", msgHandler.PairPhone)
+	group.POST("/session/logout", msgHandler.Logout)
+
+	// Message routes
+	group.POST("/messages/text", msgHandler.SendText)
+	group.POST("/messages/image", msgHandler.SendImage)
+	group.POST("/messages/video", msgHandler.SendVideo)
+	group.POST("/messages/document", msgHandler.SendDocument)
+	group.POST("/messages/audio", msgHandler.SendAudio)
+	group.POST("/messages/sticker", msgHandler.SendSticker)
+	group.POST("/messages/image/json", msgHandler.SendImageJSON)
+	group.POST("/messages/video/json", msgHandler.SendVideoJSON)
+	group.POST("/messages/document/json", msgHandler.SendDocumentJSON)
+	group.POST("/messages/audio/json", msgHandler.SendAudioJSON)
+	group.POST("/messages/sticker/json", msgHandler.SendStickerJSON)
+	group.POST("/messages/location", msgHandler.SendLocation)
+	group.POST("/messages/contact", msgHandler.SendContact)
+	group.POST("/messages/poll", msgHandler.SendPoll)
+	group.POST("/messages/reaction", msgHandler.SendReaction)
+	group.POST("/messages/edit", msgHandler.EditMessage)
+	group.POST("/messages/revoke", msgHandler.RevokeMessage)
+
+	// Poll routes
+	group.GET("/polls/:id/results", msgHandler.GetPollResults)
+
+	// Chat routes
+	group.GET("/chats/:jid/messages", msgHandler.GetChatMessages)
+}